@@ -0,0 +1,152 @@
+package browser
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sync"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/model"
+)
+
+// runtimeRequestTypes lists the resource types reported as runtime endpoints. Scripts
+// are reported through their bodies instead, and static assets are ignored.
+var runtimeRequestTypes = map[network.ResourceType]struct{}{
+	network.ResourceTypeDocument:    {},
+	network.ResourceTypeXHR:         {},
+	network.ResourceTypeFetch:       {},
+	network.ResourceTypeEventSource: {},
+	network.ResourceTypeWebSocket:   {},
+	network.ResourceTypePing:        {},
+	network.ResourceTypeOther:       {},
+}
+
+// requestRecorder captures the requests issued by a page and remembers which
+// responses were scripts so their bodies can be collected once the page settles.
+type requestRecorder struct {
+	pageURL string
+
+	mu       sync.Mutex
+	requests []model.Request
+	scripts  []scriptResponse
+	seen     map[network.RequestID]struct{}
+}
+
+type scriptResponse struct {
+	id  network.RequestID
+	url string
+}
+
+func newRequestRecorder(pageURL string) *requestRecorder {
+	return &requestRecorder{pageURL: pageURL, seen: make(map[network.RequestID]struct{})}
+}
+
+func (r *requestRecorder) actionAttach() chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		chromedp.ListenTarget(ctx, func(ev interface{}) {
+			switch e := ev.(type) {
+			case *network.EventRequestWillBeSent:
+				r.recordRequest(e)
+			case *network.EventWebSocketCreated:
+				r.record(model.Request{Method: "GET", URL: e.URL, Type: string(network.ResourceTypeWebSocket)})
+			case *network.EventResponseReceived:
+				if e.Type != network.ResourceTypeScript || e.Response == nil || !isHTTPURL(e.Response.URL) {
+					return
+				}
+				r.mu.Lock()
+				if _, ok := r.seen[e.RequestID]; !ok {
+					r.seen[e.RequestID] = struct{}{}
+					r.scripts = append(r.scripts, scriptResponse{id: e.RequestID, url: e.Response.URL})
+				}
+				r.mu.Unlock()
+			}
+		})
+		return nil
+	})
+}
+
+func (r *requestRecorder) recordRequest(ev *network.EventRequestWillBeSent) {
+	if ev.Request == nil || !isHTTPURL(ev.Request.URL) {
+		return
+	}
+	if _, ok := runtimeRequestTypes[ev.Type]; !ok {
+		return
+	}
+	if ev.Type == network.ResourceTypeDocument && ev.Request.URL == r.pageURL {
+		return
+	}
+
+	req := model.Request{
+		Method: ev.Request.Method,
+		URL:    ev.Request.URL,
+		Type:   string(ev.Type),
+	}
+
+	if len(ev.Request.Headers) > 0 {
+		req.Headers = make(map[string]string, len(ev.Request.Headers))
+		for name, value := range ev.Request.Headers {
+			req.Headers[name] = fmt.Sprint(value)
+		}
+	}
+
+	if body, err := postData(ev.Request); err == nil && len(body) > 0 {
+		req.PostData = string(body)
+	}
+
+	r.record(req)
+}
+
+func (r *requestRecorder) record(req model.Request) {
+	r.mu.Lock()
+	r.requests = append(r.requests, req)
+	r.mu.Unlock()
+}
+
+// Requests returns the runtime requests captured so far.
+func (r *requestRecorder) Requests() []model.Request {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]model.Request(nil), r.requests...)
+}
+
+// collectScripts retrieves the bodies of every script loaded by the page. Scripts
+// whose body is no longer available are skipped.
+func (r *requestRecorder) collectScripts(out *[]model.Target) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		r.mu.Lock()
+		scripts := append([]scriptResponse(nil), r.scripts...)
+		r.mu.Unlock()
+
+		for _, script := range scripts {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			body, err := network.GetResponseBody(script.id).Do(ctx)
+			if err != nil {
+				continue
+			}
+			*out = append(*out, model.Target{URL: script.url, Content: string(body), Prefetched: true})
+		}
+		return nil
+	})
+}
+
+// postData joins the request body entries reported by the DevTools protocol.
+func postData(r *network.Request) ([]byte, error) {
+	var body bytes.Buffer
+	for _, entry := range r.PostDataEntries {
+		if entry == nil || entry.Bytes == "" {
+			continue
+		}
+		decoded, err := base64.StdEncoding.DecodeString(entry.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid post data: %w", err)
+		}
+		body.Write(decoded)
+	}
+	return body.Bytes(), nil
+}
@@ -0,0 +1,50 @@
+package browser
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/chromedp/cdproto/network"
+)
+
+func TestRequestRecorderFiltersResourceTypes(t *testing.T) {
+	recorder := newRequestRecorder("https://example.com/")
+
+	body := base64.StdEncoding.EncodeToString([]byte(`{"user":"admin"}`))
+	events := []*network.EventRequestWillBeSent{
+		{Type: network.ResourceTypeDocument, Request: &network.Request{Method: "GET", URL: "https://example.com/"}},
+		{Type: network.ResourceTypeScript, Request: &network.Request{Method: "GET", URL: "https://example.com/app.js"}},
+		{Type: network.ResourceTypeImage, Request: &network.Request{Method: "GET", URL: "https://example.com/logo.png"}},
+		{Type: network.ResourceTypeXHR, Request: &network.Request{
+			Method:          "POST",
+			URL:             "https://example.com/api/login",
+			Headers:         network.Headers{"Content-Type": "application/json"},
+			PostDataEntries: []*network.PostDataEntry{{Bytes: body}},
+		}},
+		{Type: network.ResourceTypeFetch, Request: &network.Request{Method: "GET", URL: "data:text/plain,hello"}},
+		{Type: network.ResourceTypeDocument, Request: &network.Request{Method: "GET", URL: "https://example.com/frame.html"}},
+	}
+	for _, ev := range events {
+		recorder.recordRequest(ev)
+	}
+
+	requests := recorder.Requests()
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 runtime requests, got %d: %+v", len(requests), requests)
+	}
+
+	login := requests[0]
+	if login.Method != "POST" || login.URL != "https://example.com/api/login" || login.Type != "XHR" {
+		t.Fatalf("unexpected XHR request: %+v", login)
+	}
+	if login.Headers["Content-Type"] != "application/json" {
+		t.Fatalf("expected request headers to be recorded, got %v", login.Headers)
+	}
+	if login.PostData != `{"user":"admin"}` {
+		t.Fatalf("expected decoded post body, got %q", login.PostData)
+	}
+
+	if requests[1].URL != "https://example.com/frame.html" {
+		t.Fatalf("expected sub-document to be recorded, got %+v", requests[1])
+	}
+}
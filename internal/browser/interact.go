@@ -0,0 +1,290 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chromedp/chromedp"
+	"gopkg.in/yaml.v3"
+
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/config"
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/scope"
+)
+
+// Interaction step actions supported by --interactions scripts.
+const (
+	StepWaitForSelector = "wait-for-selector"
+	StepClick           = "click"
+	StepType            = "type"
+	StepScroll          = "scroll"
+	StepSleep           = "sleep"
+)
+
+const (
+	defaultStepTimeout = 5 * time.Second
+	autoSettleTimeout  = 2 * time.Second
+)
+
+// Step is a single entry of an interaction script.
+type Step struct {
+	Action   string `yaml:"action"`
+	Selector string `yaml:"selector"`
+	Value    string `yaml:"value"`
+	Timeout  string `yaml:"timeout"`
+	Duration string `yaml:"duration"`
+	Optional bool   `yaml:"optional"`
+
+	timeout  time.Duration
+	duration time.Duration
+}
+
+var (
+	scriptMu     sync.Mutex
+	scriptPath   string
+	cachedScript []Step
+)
+
+// LoadInteractions reads and validates the interaction script at path. The script is a
+// YAML or JSON list of steps. Results are cached so every render shares one parse.
+func LoadInteractions(path string) ([]Step, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	scriptMu.Lock()
+	defer scriptMu.Unlock()
+
+	if cachedScript != nil && scriptPath == path {
+		return cachedScript, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	steps, err := parseInteractions(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	scriptPath, cachedScript = path, steps
+	return steps, nil
+}
+
+func parseInteractions(data []byte) ([]Step, error) {
+	var steps []Step
+	if err := yaml.Unmarshal(data, &steps); err != nil {
+		return nil, err
+	}
+	if len(steps) == 0 {
+		return nil, errors.New("interaction script has no steps")
+	}
+
+	for idx := range steps {
+		step := &steps[idx]
+		step.Action = strings.ToLower(strings.TrimSpace(step.Action))
+
+		switch step.Action {
+		case StepWaitForSelector, StepClick, StepType:
+			if step.Selector == "" {
+				return nil, fmt.Errorf("step %d: %s requires a selector", idx+1, step.Action)
+			}
+		case StepScroll:
+		case StepSleep:
+			if step.Duration == "" {
+				return nil, fmt.Errorf("step %d: sleep requires a duration", idx+1)
+			}
+		default:
+			return nil, fmt.Errorf("step %d: unknown action %q", idx+1, step.Action)
+		}
+
+		step.timeout = defaultStepTimeout
+		if step.Timeout != "" {
+			timeout, err := time.ParseDuration(step.Timeout)
+			if err != nil || timeout <= 0 {
+				return nil, fmt.Errorf("step %d: invalid timeout %q", idx+1, step.Timeout)
+			}
+			step.timeout = timeout
+		}
+
+		if step.Duration != "" {
+			duration, err := time.ParseDuration(step.Duration)
+			if err != nil || duration < 0 {
+				return nil, fmt.Errorf("step %d: invalid duration %q", idx+1, step.Duration)
+			}
+			step.duration = duration
+		}
+	}
+
+	return steps, nil
+}
+
+// action translates the step into chromedp actions.
+func (s Step) action() chromedp.Action {
+	switch s.Action {
+	case StepWaitForSelector:
+		return chromedp.WaitVisible(s.Selector, chromedp.ByQuery)
+	case StepClick:
+		return chromedp.Click(s.Selector, chromedp.ByQuery, chromedp.NodeVisible)
+	case StepType:
+		return chromedp.SendKeys(s.Selector, s.Value, chromedp.ByQuery, chromedp.NodeVisible)
+	case StepScroll:
+		if s.Selector != "" {
+			return chromedp.ScrollIntoView(s.Selector, chromedp.ByQuery)
+		}
+		return chromedp.Evaluate(`window.scrollTo(0, document.body.scrollHeight)`, nil)
+	default:
+		return chromedp.Sleep(s.duration)
+	}
+}
+
+// interactionBudget is the time reserved after the page loaded for the script steps and
+// up to autoInteract automatic clicks, each followed by the network settling.
+func interactionBudget(steps []Step, autoInteract int) time.Duration {
+	var budget time.Duration
+	for _, step := range steps {
+		budget += step.timeout + step.duration + autoSettleTimeout
+	}
+	if autoInteract > 0 {
+		budget += time.Duration(autoInteract) * (defaultStepTimeout + autoSettleTimeout)
+	}
+	return budget
+}
+
+// interactionsAction runs the script steps in order, letting the network settle after
+// each one. Failing steps abort the render unless they are marked optional.
+func interactionsAction(steps []Step, tracker *networkIdleTracker) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		for idx, step := range steps {
+			stepCtx, cancel := context.WithTimeout(ctx, step.timeout+step.duration)
+			err := step.action().Do(stepCtx)
+			cancel()
+
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				if step.Optional {
+					continue
+				}
+				return fmt.Errorf("interaction step %d (%s): %w", idx+1, step.Action, err)
+			}
+
+			if err := tracker.waitAction(autoSettleTimeout).Do(ctx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// navigationGuardScript stops clicks and form submissions from leaving the page
+// unless a client-side router already handled them. It is registered as the last
+// listener on window, so routers see the event first.
+const navigationGuardScript = `(() => {
+	if (window.__glfGuard) { return; }
+	window.__glfGuard = true;
+	window.addEventListener('click', (event) => {
+		const link = event.target && event.target.closest ? event.target.closest('a[href]') : null;
+		if (link && !event.defaultPrevented) { event.preventDefault(); }
+	});
+	window.addEventListener('submit', (event) => {
+		if (!event.defaultPrevented) { event.preventDefault(); }
+	});
+})()`
+
+// autoCandidatesScript tags the visible navigation links and buttons with an index
+// and returns their targets. Elements that look destructive are left alone.
+const autoCandidatesScript = `(() => {
+	const selector = 'nav a, header a, [role="navigation"] a, [role="menu"] a, [role="menuitem"], [role="tab"], button, [role="button"], summary';
+	const risky = /log\s*out|sign\s*out|delete|remove/i;
+	const out = [];
+	document.querySelectorAll(selector).forEach((el) => {
+		if (el.hasAttribute('data-glf-auto') || el.disabled) { return; }
+		if (el.closest('form') && (el.type === 'submit' || el.type === 'reset')) { return; }
+		const rect = el.getBoundingClientRect();
+		const style = window.getComputedStyle(el);
+		if (rect.width === 0 || rect.height === 0 || style.visibility === 'hidden' || style.display === 'none') { return; }
+		if (risky.test(el.textContent || '')) { return; }
+		const index = window.__glfAutoNext = (window.__glfAutoNext || 0) + 1;
+		el.setAttribute('data-glf-auto', String(index));
+		out.push({index: index, href: typeof el.href === 'string' ? el.href : ''});
+	});
+	return out;
+})()`
+
+type autoCandidate struct {
+	Index int    `json:"index"`
+	Href  string `json:"href"`
+}
+
+// autoInteractAction clicks up to budget visible navigation links and buttons whose
+// targets are in scope, snapshotting the DOM after each click so content revealed by
+// one route is not lost when the next one replaces it. Clicking stops early when the
+// tab deadline leaves no room for another click, so the page rendered so far is kept.
+func autoInteractAction(cfg config.Config, pageURL string, tracker *networkIdleTracker, snapshots *[]string) chromedp.Action {
+	scopeDomain := cfg.Scope
+	includeSubdomains := cfg.ScopeIncludeSubdomains
+	if scopeDomain == "" {
+		scopeDomain = pageURL
+		includeSubdomains = false
+	}
+
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		if err := chromedp.Evaluate(navigationGuardScript, nil).Do(ctx); err != nil {
+			return err
+		}
+
+		clicks := 0
+		for clicks < cfg.AutoInteract {
+			var candidates []autoCandidate
+			if err := chromedp.Evaluate(autoCandidatesScript, &candidates).Do(ctx); err != nil {
+				return err
+			}
+			if len(candidates) == 0 {
+				return nil
+			}
+
+			for _, candidate := range candidates {
+				if clicks >= cfg.AutoInteract {
+					break
+				}
+				if candidate.Href != "" && !scope.Within(candidate.Href, scopeDomain, includeSubdomains) {
+					continue
+				}
+
+				if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < defaultStepTimeout+autoSettleTimeout {
+					return nil
+				}
+
+				selector := fmt.Sprintf(`[data-glf-auto="%d"]`, candidate.Index)
+				clickCtx, cancel := context.WithTimeout(ctx, defaultStepTimeout)
+				err := chromedp.Click(selector, chromedp.ByQuery, chromedp.AtLeast(0)).Do(clickCtx)
+				cancel()
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				clicks++
+				if err != nil {
+					continue
+				}
+
+				if err := tracker.waitAction(autoSettleTimeout).Do(ctx); err != nil {
+					return err
+				}
+
+				var html string
+				if err := chromedp.OuterHTML("html", &html, chromedp.ByQuery).Do(ctx); err == nil {
+					*snapshots = append(*snapshots, html)
+				}
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,103 @@
+package browser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseInteractionsYAMLAndJSON(t *testing.T) {
+	yamlScript := []byte(`
+- action: wait-for-selector
+  selector: "#app"
+  timeout: 3s
+- action: click
+  selector: nav .menu
+- action: type
+  selector: input[name=q]
+  value: admin
+- action: scroll
+- action: sleep
+  duration: 500ms
+  optional: true
+`)
+
+	steps, err := parseInteractions(yamlScript)
+	if err != nil {
+		t.Fatalf("unexpected error parsing YAML: %v", err)
+	}
+	if len(steps) != 5 {
+		t.Fatalf("expected 5 steps, got %d", len(steps))
+	}
+	if steps[0].timeout != 3*time.Second {
+		t.Fatalf("expected custom timeout, got %s", steps[0].timeout)
+	}
+	if steps[1].timeout != defaultStepTimeout {
+		t.Fatalf("expected default timeout, got %s", steps[1].timeout)
+	}
+	if steps[2].Value != "admin" {
+		t.Fatalf("expected typed value to be kept, got %q", steps[2].Value)
+	}
+	if steps[4].duration != 500*time.Millisecond || !steps[4].Optional {
+		t.Fatalf("unexpected sleep step: %+v", steps[4])
+	}
+
+	jsonScript := []byte(`[{"action": "Click", "selector": "#menu"}, {"action": "sleep", "duration": "1s"}]`)
+	steps, err = parseInteractions(jsonScript)
+	if err != nil {
+		t.Fatalf("unexpected error parsing JSON: %v", err)
+	}
+	if len(steps) != 2 || steps[0].Action != StepClick {
+		t.Fatalf("unexpected JSON steps: %+v", steps)
+	}
+}
+
+func TestParseInteractionsErrors(t *testing.T) {
+	cases := map[string]string{
+		"empty":            `[]`,
+		"unknown action":   `[{"action": "hover", "selector": "a"}]`,
+		"missing selector": `[{"action": "click"}]`,
+		"missing duration": `[{"action": "sleep"}]`,
+		"bad timeout":      `[{"action": "click", "selector": "a", "timeout": "soon"}]`,
+	}
+
+	for name, script := range cases {
+		if _, err := parseInteractions([]byte(script)); err == nil {
+			t.Errorf("%s: expected error, got nil", name)
+		}
+	}
+}
+
+func TestLoadInteractionsPrefixesPath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "steps.yaml")
+	if err := os.WriteFile(path, []byte("- action: click\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := LoadInteractions(path)
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	if got := err.Error(); !strings.HasPrefix(got, path) {
+		t.Fatalf("expected error to start with the script path, got %q", got)
+	}
+}
+
+func TestInteractionBudget(t *testing.T) {
+	steps, err := parseInteractions([]byte(`[{"action": "click", "selector": "a", "timeout": "3s"}, {"action": "sleep", "duration": "1s"}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := interactionBudget(nil, 0); got != 0 {
+		t.Fatalf("expected no budget without interactions, got %v", got)
+	}
+
+	want := 3*time.Second + autoSettleTimeout + defaultStepTimeout + time.Second + autoSettleTimeout +
+		2*(defaultStepTimeout+autoSettleTimeout)
+	if got := interactionBudget(steps, 2); got != want {
+		t.Fatalf("expected budget %v, got %v", want, got)
+	}
+}
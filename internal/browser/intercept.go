@@ -0,0 +1,145 @@
+package browser
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/config"
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/resolve"
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/tlsconfig"
+)
+
+// needsInterception reports whether cfg holds network settings that Chromium cannot
+// express through command line flags.
+func needsInterception(cfg config.Config) bool {
+	return tlsconfig.HasCustomMaterial(cfg) || cfg.DNSServer != ""
+}
+
+// newInterceptClient builds the HTTP client used to fulfil intercepted browser
+// requests. Redirects are handed back to Chromium so it can follow them itself.
+func newInterceptClient(cfg config.Config, proxyURL *url.URL, timeout time.Duration) (*http.Client, error) {
+	base, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return nil, errors.New("unexpected default transport type")
+	}
+
+	transport := base.Clone()
+	// Chromium negotiates and decodes content encodings on its own.
+	transport.DisableCompression = true
+
+	tlsCfg, err := tlsconfig.Build(cfg)
+	if err != nil {
+		return nil, err
+	}
+	transport.TLSClientConfig = tlsCfg
+
+	if dial := resolve.DialContext(cfg); dial != nil {
+		transport.DialContext = dial
+	}
+
+	if proxyURL != nil {
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}, nil
+}
+
+// interceptAction routes every browser request through client so that settings
+// Chromium cannot load from flags, such as client certificates, private CAs and
+// custom DNS servers, are honoured.
+func interceptAction(client *http.Client) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		chromedp.ListenTarget(ctx, func(ev interface{}) {
+			if e, ok := ev.(*fetch.EventRequestPaused); ok {
+				go fulfilRequest(executorContext(ctx), client, e)
+			}
+		})
+
+		patterns := []*fetch.RequestPattern{{URLPattern: "*", RequestStage: fetch.RequestStageRequest}}
+		return fetch.Enable().WithPatterns(patterns).Do(ctx)
+	})
+}
+
+func fulfilRequest(ctx context.Context, client *http.Client, ev *fetch.EventRequestPaused) {
+	if ev.Request == nil || !isHTTPURL(ev.Request.URL) {
+		_ = fetch.ContinueRequest(ev.RequestID).Do(ctx)
+		return
+	}
+
+	req, err := interceptedRequest(ctx, ev.Request)
+	if err != nil {
+		_ = fetch.FailRequest(ev.RequestID, network.ErrorReasonFailed).Do(ctx)
+		return
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		_ = fetch.FailRequest(ev.RequestID, network.ErrorReasonConnectionFailed).Do(ctx)
+		return
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		_ = fetch.FailRequest(ev.RequestID, network.ErrorReasonFailed).Do(ctx)
+		return
+	}
+
+	headers := make([]*fetch.HeaderEntry, 0, len(resp.Header))
+	for name, values := range resp.Header {
+		for _, value := range values {
+			headers = append(headers, &fetch.HeaderEntry{Name: name, Value: value})
+		}
+	}
+
+	_ = fetch.FulfillRequest(ev.RequestID, int64(resp.StatusCode)).
+		WithResponseHeaders(headers).
+		WithBody(base64.StdEncoding.EncodeToString(body)).
+		Do(ctx)
+}
+
+func interceptedRequest(ctx context.Context, r *network.Request) (*http.Request, error) {
+	body, err := postData(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var reader io.Reader
+	if len(body) > 0 {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, r.Method, r.URL, reader)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, value := range r.Headers {
+		req.Header.Set(name, fmt.Sprint(value))
+	}
+
+	return req, nil
+}
+
+func isHTTPURL(raw string) bool {
+	lowered := strings.ToLower(raw)
+	return strings.HasPrefix(lowered, "http://") || strings.HasPrefix(lowered, "https://")
+}
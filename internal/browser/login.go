@@ -0,0 +1,389 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+	"github.com/chromedp/chromedp/kb"
+	"gopkg.in/yaml.v3"
+
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/config"
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/model"
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/proxy"
+)
+
+const (
+	defaultLoginTimeout = 30 * time.Second
+	loginPollInterval   = 250 * time.Millisecond
+)
+
+// LoginRecipe describes how to sign in to an application from the headless browser.
+type LoginRecipe struct {
+	URL     string        `yaml:"url"`
+	Fields  []LoginField  `yaml:"fields"`
+	Submit  string        `yaml:"submit"`
+	Success LoginSuccess  `yaml:"success"`
+	Headers []LoginHeader `yaml:"headers"`
+	Timeout string        `yaml:"timeout"`
+
+	timeout time.Duration
+}
+
+// LoginField fills the element matched by Selector with the content of the Env
+// environment variable, or with Value for fields that are not secret.
+type LoginField struct {
+	Selector string `yaml:"selector"`
+	Env      string `yaml:"env"`
+	Value    string `yaml:"value"`
+}
+
+// LoginSuccess lists the conditions that must all hold once the form is submitted.
+type LoginSuccess struct {
+	// Selector must match a visible element.
+	Selector string `yaml:"selector"`
+	// URL must be contained in the page location.
+	URL string `yaml:"url"`
+	// Cookie must be set for the page.
+	Cookie string `yaml:"cookie"`
+}
+
+// LoginHeader is sent by every fetcher after logging in. Value may reference the
+// captured session with {{localStorage.key}}, {{sessionStorage.key}} or {{cookie.name}}.
+type LoginHeader struct {
+	Name  string `yaml:"name"`
+	Value string `yaml:"value"`
+}
+
+// LoadLoginRecipe reads and validates the login recipe at path.
+func LoadLoginRecipe(path string) (LoginRecipe, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return LoginRecipe{}, err
+	}
+
+	recipe, err := parseLoginRecipe(data)
+	if err != nil {
+		return LoginRecipe{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return recipe, nil
+}
+
+func parseLoginRecipe(data []byte) (LoginRecipe, error) {
+	var recipe LoginRecipe
+	if err := yaml.Unmarshal(data, &recipe); err != nil {
+		return LoginRecipe{}, err
+	}
+
+	parsed, err := url.Parse(recipe.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return LoginRecipe{}, fmt.Errorf("invalid login url %q", recipe.URL)
+	}
+
+	if len(recipe.Fields) == 0 {
+		return LoginRecipe{}, errors.New("login recipe has no fields")
+	}
+	for idx, field := range recipe.Fields {
+		if field.Selector == "" {
+			return LoginRecipe{}, fmt.Errorf("field %d: selector is required", idx+1)
+		}
+		if (field.Env == "") == (field.Value == "") {
+			return LoginRecipe{}, fmt.Errorf("field %d: exactly one of env or value is required", idx+1)
+		}
+	}
+
+	if recipe.Success == (LoginSuccess{}) {
+		return LoginRecipe{}, errors.New("login recipe needs a success condition (selector, url or cookie)")
+	}
+
+	for idx, header := range recipe.Headers {
+		if strings.TrimSpace(header.Name) == "" {
+			return LoginRecipe{}, fmt.Errorf("header %d: name is required", idx+1)
+		}
+	}
+
+	recipe.timeout = defaultLoginTimeout
+	if recipe.Timeout != "" {
+		timeout, err := time.ParseDuration(recipe.Timeout)
+		if err != nil || timeout <= 0 {
+			return LoginRecipe{}, fmt.Errorf("invalid timeout %q", recipe.Timeout)
+		}
+		recipe.timeout = timeout
+	}
+
+	return recipe, nil
+}
+
+// fieldValues resolves the value typed into each field, reading secrets from the environment.
+func (r LoginRecipe) fieldValues() ([]string, error) {
+	values := make([]string, len(r.Fields))
+	for idx, field := range r.Fields {
+		if field.Env == "" {
+			values[idx] = field.Value
+			continue
+		}
+		value, ok := os.LookupEnv(field.Env)
+		if !ok || value == "" {
+			return nil, fmt.Errorf("environment variable %s is not set", field.Env)
+		}
+		values[idx] = value
+	}
+	return values, nil
+}
+
+// Login runs the recipe configured with --login in the headless browser and returns the
+// resulting session together with the headers the recipe derives from it.
+func Login(ctx context.Context, cfg config.Config) (*model.Session, []config.Header, error) {
+	recipe, err := LoadLoginRecipe(cfg.Login)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	values, err := recipe.fieldValues()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	proxies, err := proxy.FromConfig(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pool := getPool(cfg)
+	if err := pool.acquire(ctx); err != nil {
+		return nil, nil, err
+	}
+	defer pool.release()
+
+	t, err := openTab(ctx, pool, proxies, cfg, recipe.timeout, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer t.close()
+
+	actions := append([]chromedp.Action{}, t.setup...)
+	actions = append(actions, chromedp.Navigate(recipe.URL))
+	for idx, field := range recipe.Fields {
+		actions = append(actions,
+			chromedp.WaitVisible(field.Selector, chromedp.ByQuery),
+			chromedp.Clear(field.Selector, chromedp.ByQuery),
+			chromedp.SendKeys(field.Selector, values[idx], chromedp.ByQuery),
+		)
+	}
+	if recipe.Submit != "" {
+		actions = append(actions, chromedp.Click(recipe.Submit, chromedp.ByQuery, chromedp.NodeVisible))
+	} else {
+		last := recipe.Fields[len(recipe.Fields)-1].Selector
+		actions = append(actions, chromedp.SendKeys(last, kb.Enter, chromedp.ByQuery))
+	}
+
+	session := &model.Session{}
+	actions = append(actions,
+		waitLoginSuccess(recipe.Success),
+		captureSession(recipe.URL, session),
+	)
+
+	if _, err := t.run(proxies, actions...); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, nil, fmt.Errorf("login did not succeed within %s", recipe.timeout)
+		}
+		return nil, nil, err
+	}
+
+	headers, err := recipe.sessionHeaders(session)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return session, headers, nil
+}
+
+// waitLoginSuccess polls the page until every configured success condition holds.
+func waitLoginSuccess(success LoginSuccess) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		ticker := time.NewTicker(loginPollInterval)
+		defer ticker.Stop()
+
+		for {
+			ok, err := loginSucceeded(ctx, success)
+			if err != nil {
+				return err
+			}
+			if ok {
+				return nil
+			}
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-ticker.C:
+			}
+		}
+	})
+}
+
+func loginSucceeded(ctx context.Context, success LoginSuccess) (bool, error) {
+	if success.Selector != "" {
+		selector, _ := json.Marshal(success.Selector)
+		var visible bool
+		script := fmt.Sprintf(`(() => { const el = document.querySelector(%s); return !!el && el.getClientRects().length > 0; })()`, selector)
+		if err := chromedp.Evaluate(script, &visible).Do(ctx); err != nil || !visible {
+			return false, nil
+		}
+	}
+
+	if success.URL != "" {
+		var location string
+		if err := chromedp.Location(&location).Do(ctx); err != nil || !strings.Contains(location, success.URL) {
+			return false, nil
+		}
+	}
+
+	if success.Cookie != "" {
+		cookies, err := network.GetCookies().Do(ctx)
+		if err != nil {
+			return false, err
+		}
+		for _, cookie := range cookies {
+			if cookie.Name == success.Cookie {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// captureSession records the cookies of the login and landing pages and the storage of
+// the landing page's origin.
+func captureSession(loginURL string, session *model.Session) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		var location string
+		if err := chromedp.Location(&location).Do(ctx); err != nil {
+			return err
+		}
+
+		cookies, err := network.GetCookies().WithURLs([]string{loginURL, location}).Do(ctx)
+		if err != nil {
+			return err
+		}
+		for _, cookie := range cookies {
+			session.Cookies = append(session.Cookies, model.Cookie{
+				Name:     cookie.Name,
+				Value:    cookie.Value,
+				Domain:   cookie.Domain,
+				Path:     cookie.Path,
+				Secure:   cookie.Secure,
+				HTTPOnly: cookie.HTTPOnly,
+			})
+		}
+
+		var storage storageDump
+		if err := chromedp.Evaluate(storageDumpScript, &storage).Do(ctx); err != nil {
+			return err
+		}
+
+		session.Origin = storage.Origin
+		session.LocalStorage = storage.Local
+		session.SessionStorage = storage.Session
+		return nil
+	})
+}
+
+var sessionReference = regexp.MustCompile(`\{\{\s*(localStorage|sessionStorage|cookie)\.([^}\s]+)\s*\}\}`)
+
+// sessionHeaders expands the recipe's header templates with values from the session.
+func (r LoginRecipe) sessionHeaders(session *model.Session) ([]config.Header, error) {
+	headers := make([]config.Header, 0, len(r.Headers))
+	for _, header := range r.Headers {
+		var missing string
+		value := sessionReference.ReplaceAllStringFunc(header.Value, func(ref string) string {
+			match := sessionReference.FindStringSubmatch(ref)
+			source, key := match[1], match[2]
+
+			var (
+				found string
+				ok    bool
+			)
+			switch source {
+			case "localStorage":
+				found, ok = session.LocalStorage[key]
+			case "sessionStorage":
+				found, ok = session.SessionStorage[key]
+			case "cookie":
+				for _, cookie := range session.Cookies {
+					if cookie.Name == key {
+						found, ok = cookie.Value, true
+						break
+					}
+				}
+			}
+			if !ok && missing == "" {
+				missing = source + "." + key
+			}
+			return found
+		})
+
+		if missing != "" {
+			return nil, fmt.Errorf("header %s references %s, which the login did not produce", header.Name, missing)
+		}
+		headers = append(headers, config.Header{Name: strings.TrimSpace(header.Name), Value: value})
+	}
+	return headers, nil
+}
+
+// sessionAction seeds a tab with the cookies and storage captured at login. Storage is
+// written by a script that runs before the page's own scripts on the session's origin.
+func sessionAction(session *model.Session) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		if len(session.Cookies) > 0 {
+			params := make([]*network.CookieParam, 0, len(session.Cookies))
+			for _, cookie := range session.Cookies {
+				params = append(params, &network.CookieParam{
+					Name:     cookie.Name,
+					Value:    cookie.Value,
+					Domain:   cookie.Domain,
+					Path:     cookie.Path,
+					Secure:   cookie.Secure,
+					HTTPOnly: cookie.HTTPOnly,
+				})
+			}
+			if err := network.SetCookies(params).Do(ctx); err != nil {
+				return err
+			}
+		}
+
+		if session.Origin == "" || (len(session.LocalStorage) == 0 && len(session.SessionStorage) == 0) {
+			return nil
+		}
+
+		_, err := page.AddScriptToEvaluateOnNewDocument(storageSeedScript(session)).Do(ctx)
+		return err
+	})
+}
+
+func storageSeedScript(session *model.Session) string {
+	origin, _ := json.Marshal(session.Origin)
+	local, _ := json.Marshal(session.LocalStorage)
+	sess, _ := json.Marshal(session.SessionStorage)
+
+	return fmt.Sprintf(`(() => {
+	if (location.origin !== %s) { return; }
+	try {
+		const local = %s || {};
+		for (const key of Object.keys(local)) { localStorage.setItem(key, local[key]); }
+		const session = %s || {};
+		for (const key of Object.keys(session)) { sessionStorage.setItem(key, session[key]); }
+	} catch (e) {}
+})()`, origin, local, sess)
+}
@@ -0,0 +1,117 @@
+package browser
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/model"
+)
+
+func TestParseLoginRecipe(t *testing.T) {
+	recipe, err := parseLoginRecipe([]byte(`
+url: https://app.example.com/login
+fields:
+  - selector: "#email"
+    env: APP_USER
+  - selector: "#password"
+    env: APP_PASS
+  - selector: "#tenant"
+    value: acme
+submit: button[type=submit]
+success:
+  selector: .dashboard
+  cookie: session
+headers:
+  - name: Authorization
+    value: "Bearer {{ localStorage.access_token }}"
+timeout: 45s
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(recipe.Fields) != 3 || recipe.Fields[2].Value != "acme" {
+		t.Fatalf("unexpected fields: %+v", recipe.Fields)
+	}
+	if recipe.timeout != 45*time.Second {
+		t.Fatalf("expected custom timeout, got %s", recipe.timeout)
+	}
+
+	t.Setenv("APP_USER", "admin@example.com")
+	t.Setenv("APP_PASS", "s3cret")
+	values, err := recipe.fieldValues()
+	if err != nil {
+		t.Fatalf("unexpected error resolving field values: %v", err)
+	}
+	if strings.Join(values, ",") != "admin@example.com,s3cret,acme" {
+		t.Fatalf("unexpected field values: %v", values)
+	}
+}
+
+func TestParseLoginRecipeErrors(t *testing.T) {
+	cases := map[string]string{
+		"missing url":        `{"fields": [{"selector": "#u", "value": "a"}], "success": {"url": "/home"}}`,
+		"no fields":          `{"url": "https://example.com/login", "success": {"url": "/home"}}`,
+		"env and value":      `{"url": "https://example.com/login", "fields": [{"selector": "#u", "env": "U", "value": "a"}], "success": {"url": "/home"}}`,
+		"no success":         `{"url": "https://example.com/login", "fields": [{"selector": "#u", "value": "a"}]}`,
+		"unnamed header":     `{"url": "https://example.com/login", "fields": [{"selector": "#u", "value": "a"}], "success": {"url": "/home"}, "headers": [{"value": "x"}]}`,
+		"invalid timeout":    `{"url": "https://example.com/login", "fields": [{"selector": "#u", "value": "a"}], "success": {"url": "/home"}, "timeout": "later"}`,
+		"non http login url": `{"url": "ftp://example.com", "fields": [{"selector": "#u", "value": "a"}], "success": {"url": "/home"}}`,
+	}
+
+	for name, recipe := range cases {
+		if _, err := parseLoginRecipe([]byte(recipe)); err == nil {
+			t.Errorf("%s: expected error, got nil", name)
+		}
+	}
+}
+
+func TestLoginFieldValuesRequireEnvironment(t *testing.T) {
+	recipe := LoginRecipe{Fields: []LoginField{{Selector: "#p", Env: "GLF_TEST_UNSET_PASSWORD"}}}
+	if _, err := recipe.fieldValues(); err == nil || !strings.Contains(err.Error(), "GLF_TEST_UNSET_PASSWORD") {
+		t.Fatalf("expected missing variable error, got %v", err)
+	}
+}
+
+func TestSessionHeaders(t *testing.T) {
+	session := &model.Session{
+		Cookies:        []model.Cookie{{Name: "csrf", Value: "c-123"}},
+		LocalStorage:   map[string]string{"access_token": "tok-abc"},
+		SessionStorage: map[string]string{"tenant": "acme"},
+	}
+
+	recipe := LoginRecipe{Headers: []LoginHeader{
+		{Name: "Authorization", Value: "Bearer {{localStorage.access_token}}"},
+		{Name: "X-Context", Value: "{{ sessionStorage.tenant }}/{{cookie.csrf}}"},
+	}}
+
+	headers, err := recipe.sessionHeaders(session)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if headers[0].Value != "Bearer tok-abc" {
+		t.Fatalf("unexpected Authorization value: %q", headers[0].Value)
+	}
+	if headers[1].Value != "acme/c-123" {
+		t.Fatalf("unexpected X-Context value: %q", headers[1].Value)
+	}
+
+	recipe.Headers = []LoginHeader{{Name: "Authorization", Value: "Bearer {{localStorage.id_token}}"}}
+	if _, err := recipe.sessionHeaders(session); err == nil {
+		t.Fatal("expected error for a token the login did not produce")
+	}
+}
+
+func TestStorageSeedScriptIsScopedToOrigin(t *testing.T) {
+	script := storageSeedScript(&model.Session{
+		Origin:       "https://app.example.com",
+		LocalStorage: map[string]string{"token": `a"b`},
+	})
+
+	if !strings.Contains(script, `location.origin !== "https://app.example.com"`) {
+		t.Fatalf("expected origin guard in script:\n%s", script)
+	}
+	if !strings.Contains(script, `{"token":"a\"b"}`) {
+		t.Fatalf("expected JSON encoded storage in script:\n%s", script)
+	}
+}
@@ -0,0 +1,217 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/target"
+	"github.com/chromedp/chromedp"
+
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/config"
+)
+
+const shutdownTimeout = 5 * time.Second
+
+// poolSettings captures the options that require a browser restart when they change.
+type poolSettings struct {
+	insecure bool
+	resolve  string
+	size     int
+}
+
+// browserPool keeps a single Chromium process alive for the whole run and hands out
+// up to size tabs at a time. Each render gets its own browser context so cookies,
+// storage and proxy settings never leak between targets.
+type browserPool struct {
+	settings poolSettings
+	slots    chan struct{}
+
+	mu            sync.Mutex
+	cancelAlloc   context.CancelFunc
+	browserCtx    context.Context
+	cancelBrowser context.CancelFunc
+	// active counts the renders holding a slot. A retired pool closes once it
+	// drops to zero.
+	active  int
+	retired bool
+}
+
+var (
+	poolMu     sync.Mutex
+	sharedPool *browserPool
+)
+
+func poolSettingsFor(cfg config.Config) poolSettings {
+	size := cfg.Workers
+	if size < 1 {
+		size = 1
+	}
+	return poolSettings{
+		insecure: cfg.Insecure,
+		resolve:  hostResolverRules(cfg.Resolve),
+		size:     size,
+	}
+}
+
+// getPool returns the shared browser pool, replacing it when the configuration
+// requires a differently launched browser. The replaced pool is closed once the
+// renders still using it are done.
+func getPool(cfg config.Config) *browserPool {
+	desired := poolSettingsFor(cfg)
+
+	poolMu.Lock()
+	defer poolMu.Unlock()
+
+	if sharedPool != nil && sharedPool.settings == desired {
+		return sharedPool
+	}
+
+	if sharedPool != nil {
+		sharedPool.retire()
+	}
+
+	sharedPool = &browserPool{
+		settings: desired,
+		slots:    make(chan struct{}, desired.size),
+	}
+	return sharedPool
+}
+
+// Shutdown closes the shared browser, if one was started. It is safe to call
+// multiple times and when rendering was never used.
+func Shutdown() {
+	poolMu.Lock()
+	defer poolMu.Unlock()
+
+	if sharedPool != nil {
+		sharedPool.close()
+		sharedPool = nil
+	}
+}
+
+// acquire reserves a tab slot, waiting until one is free or ctx is done.
+func (p *browserPool) acquire(ctx context.Context) error {
+	select {
+	case p.slots <- struct{}{}:
+		p.mu.Lock()
+		p.active++
+		p.mu.Unlock()
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p *browserPool) release() {
+	<-p.slots
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.active--
+	if p.retired && p.active == 0 {
+		p.stopLocked()
+	}
+}
+
+// retire closes the pool as soon as no render holds one of its slots.
+func (p *browserPool) retire() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.retired = true
+	if p.active == 0 {
+		p.stopLocked()
+	}
+}
+
+// browser returns the context of the running browser, launching it on first use
+// and relaunching it when the previous process crashed or was closed.
+func (p *browserPool) browser() (context.Context, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.browserCtx != nil && p.browserCtx.Err() == nil {
+		return p.browserCtx, nil
+	}
+	p.stopLocked()
+
+	allocCtx, cancelAlloc := chromedp.NewExecAllocator(context.Background(), p.allocatorOptions()...)
+	browserCtx, cancelBrowser := chromedp.NewContext(allocCtx)
+
+	// Running without actions starts the browser and its initial blank tab.
+	if err := chromedp.Run(browserCtx); err != nil {
+		cancelBrowser()
+		cancelAlloc()
+		return nil, fmt.Errorf("unable to start headless browser: %w", err)
+	}
+
+	p.cancelAlloc = cancelAlloc
+	p.browserCtx, p.cancelBrowser = browserCtx, cancelBrowser
+	return browserCtx, nil
+}
+
+// newTab opens a tab in a fresh browser context. When proxyServer is not empty the
+// tab's traffic is routed through it.
+func (p *browserPool) newTab(browserCtx context.Context, proxyServer string) (context.Context, context.CancelFunc) {
+	return chromedp.NewContext(browserCtx, chromedp.WithNewBrowserContext(
+		func(params *target.CreateBrowserContextParams) *target.CreateBrowserContextParams {
+			if proxyServer == "" {
+				return params
+			}
+			return params.WithProxyServer(proxyServer)
+		},
+	))
+}
+
+func (p *browserPool) allocatorOptions() []chromedp.ExecAllocatorOption {
+	opts := append(chromedp.DefaultExecAllocatorOptions[:],
+		chromedp.NoFirstRun,
+		chromedp.NoDefaultBrowserCheck,
+		chromedp.Flag("headless", true),
+		chromedp.Flag("disable-gpu", true),
+		chromedp.Flag("disable-background-networking", true),
+		chromedp.Flag("disable-background-timer-throttling", true),
+		chromedp.Flag("disable-client-side-phishing-detection", true),
+		chromedp.Flag("disable-default-apps", true),
+		chromedp.Flag("disable-hang-monitor", true),
+		chromedp.Flag("disable-popup-blocking", true),
+		chromedp.Flag("disable-prompt-on-repost", true),
+		chromedp.Flag("disable-sync", true),
+		chromedp.Flag("metrics-recording-only", true),
+		chromedp.Flag("safebrowsing-disable-auto-update", true),
+		chromedp.Flag("disable-extensions", true),
+		chromedp.Flag("disable-dev-shm-usage", true),
+		chromedp.Flag("ignore-certificate-errors", p.settings.insecure),
+	)
+
+	if p.settings.resolve != "" {
+		opts = append(opts, chromedp.Flag("host-resolver-rules", p.settings.resolve))
+	}
+
+	if execPath, ok := findExecPath(); ok {
+		opts = append(opts, chromedp.ExecPath(execPath))
+	}
+
+	return opts
+}
+
+func (p *browserPool) close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.stopLocked()
+}
+
+func (p *browserPool) stopLocked() {
+	if p.browserCtx != nil {
+		ctx, cancel := context.WithTimeout(p.browserCtx, shutdownTimeout)
+		_ = chromedp.Cancel(ctx)
+		cancel()
+		p.cancelBrowser()
+	}
+	if p.cancelAlloc != nil {
+		p.cancelAlloc()
+	}
+	p.cancelAlloc = nil
+	p.browserCtx, p.cancelBrowser = nil, nil
+}
@@ -0,0 +1,80 @@
+package browser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/config"
+)
+
+func TestGetPoolReusesMatchingSettings(t *testing.T) {
+	defer Shutdown()
+
+	cfg := config.Config{Workers: 4}
+	first := getPool(cfg)
+	if cap(first.slots) != 4 {
+		t.Fatalf("expected pool sized to the worker count, got %d", cap(first.slots))
+	}
+
+	// Settings that only affect individual tabs must not replace the browser.
+	cfg.Headers = []config.Header{{Name: "X-Test", Value: "1"}}
+	cfg.Proxy = "http://127.0.0.1:8080"
+	if getPool(cfg) != first {
+		t.Fatal("expected the pool to be reused for tab-level settings")
+	}
+
+	cfg.Insecure = true
+	second := getPool(cfg)
+	if second == first {
+		t.Fatal("expected a new pool when launch flags change")
+	}
+
+	Shutdown()
+	if getPool(cfg) == second {
+		t.Fatal("expected Shutdown to discard the shared pool")
+	}
+}
+
+func TestGetPoolClosesReplacedPoolOnceIdle(t *testing.T) {
+	defer Shutdown()
+
+	cfg := config.Config{Workers: 1}
+	first := getPool(cfg)
+	if err := first.acquire(t.Context()); err != nil {
+		t.Fatalf("unexpected error acquiring free slot: %v", err)
+	}
+	stopped := false
+	first.cancelAlloc = func() { stopped = true }
+
+	cfg.Insecure = true
+	if getPool(cfg) == first {
+		t.Fatal("expected a new pool when launch flags change")
+	}
+	if stopped {
+		t.Fatal("expected the replaced pool to stay open while a render holds a tab")
+	}
+
+	first.release()
+	if !stopped {
+		t.Fatal("expected the replaced pool to close once its last render finished")
+	}
+}
+
+func TestPoolSlotsBoundConcurrency(t *testing.T) {
+	pool := &browserPool{slots: make(chan struct{}, 1)}
+
+	if err := pool.acquire(t.Context()); err != nil {
+		t.Fatalf("unexpected error acquiring free slot: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	if err := pool.acquire(ctx); err == nil {
+		t.Fatal("expected acquire to fail while the only slot is taken")
+	}
+
+	pool.release()
+	if err := pool.acquire(t.Context()); err != nil {
+		t.Fatalf("expected slot to be available after release: %v", err)
+	}
+}
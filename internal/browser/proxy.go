@@ -0,0 +1,69 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"net/url"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/chromedp"
+)
+
+// chromiumProxy converts a proxy URL into the value expected by Chromium's
+// --proxy-server flag. Chromium ignores credentials embedded in that flag, so they
+// are returned separately and answered through proxy authentication challenges.
+func chromiumProxy(u *url.URL) (string, *url.Userinfo, error) {
+	scheme := u.Scheme
+	if scheme == "socks5h" {
+		// Chromium always resolves hostnames through SOCKS5 proxies.
+		scheme = "socks5"
+	}
+
+	if scheme == "socks5" && u.User != nil {
+		return "", nil, errors.New("chromium does not support authenticated SOCKS proxies")
+	}
+
+	return scheme + "://" + u.Host, u.User, nil
+}
+
+// proxyAuthAction answers proxy authentication challenges with the provided credentials.
+// Enabling the fetch domain pauses every request, so paused requests are resumed untouched.
+func proxyAuthAction(user *url.Userinfo) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		password, _ := user.Password()
+		credentials := &fetch.AuthChallengeResponse{
+			Response: fetch.AuthChallengeResponseResponseProvideCredentials,
+			Username: user.Username(),
+			Password: password,
+		}
+
+		chromedp.ListenTarget(ctx, func(ev interface{}) {
+			switch e := ev.(type) {
+			case *fetch.EventRequestPaused:
+				go func() {
+					_ = fetch.ContinueRequest(e.RequestID).Do(executorContext(ctx))
+				}()
+			case *fetch.EventAuthRequired:
+				response := credentials
+				if e.AuthChallenge == nil || e.AuthChallenge.Source != fetch.AuthChallengeSourceProxy {
+					response = &fetch.AuthChallengeResponse{Response: fetch.AuthChallengeResponseResponseDefault}
+				}
+				go func() {
+					_ = fetch.ContinueWithAuth(e.RequestID, response).Do(executorContext(ctx))
+				}()
+			}
+		})
+
+		return fetch.Enable().WithHandleAuthRequests(true).Do(ctx)
+	})
+}
+
+// executorContext returns a context that can issue CDP commands from inside event listeners.
+func executorContext(ctx context.Context) context.Context {
+	c := chromedp.FromContext(ctx)
+	if c == nil || c.Target == nil {
+		return ctx
+	}
+	return cdp.WithExecutor(ctx, c.Target)
+}
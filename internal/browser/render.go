@@ -2,16 +2,22 @@ package browser
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/chromedp/cdproto/emulation"
 	"github.com/chromedp/cdproto/network"
 	"github.com/chromedp/chromedp"
 
 	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/config"
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/model"
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/proxy"
 )
 
 const (
@@ -19,65 +25,69 @@ const (
 	defaultRenderTimeout = 15 * time.Second
 )
 
-// FetchRendered loads the provided URL inside a headless browser and returns the rendered HTML.
-// The function respects proxy, cookies and timeout options provided in cfg.
-func FetchRendered(ctx context.Context, rawURL string, cfg config.Config) (string, error) {
+// Page holds the outcome of rendering a URL in the headless browser.
+type Page struct {
+	HTML string
+	// Requests lists the XHR, fetch, WebSocket and similar requests issued at runtime.
+	Requests []model.Request
+	// Scripts holds the bodies of the scripts loaded by the page as prefetched targets.
+	Scripts []model.Target
+	// State holds the matching window globals and the web storage of the page.
+	State []model.StateEntry
+}
+
+// FetchRendered loads the provided URL inside a headless browser and returns the rendered
+// page together with the requests and scripts observed while it loaded.
+// The function respects proxy, TLS, header, cookie and timeout options provided in cfg.
+// Pages are rendered in tabs of a shared browser that is started on first use and kept
+// alive until Shutdown is called.
+func FetchRendered(ctx context.Context, rawURL string, cfg config.Config) (Page, error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
 
-	timeout := cfg.Timeout
-	if timeout <= 0 {
-		timeout = defaultRenderTimeout
+	proxies, err := proxy.FromConfig(cfg)
+	if err != nil {
+		return Page{}, err
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, timeout)
-	defer cancel()
-
-	allocatorOpts := append(chromedp.DefaultExecAllocatorOptions[:],
-		chromedp.NoFirstRun,
-		chromedp.NoDefaultBrowserCheck,
-		chromedp.Flag("headless", true),
-		chromedp.Flag("disable-gpu", true),
-		chromedp.Flag("disable-background-networking", true),
-		chromedp.Flag("disable-background-timer-throttling", true),
-		chromedp.Flag("disable-client-side-phishing-detection", true),
-		chromedp.Flag("disable-default-apps", true),
-		chromedp.Flag("disable-hang-monitor", true),
-		chromedp.Flag("disable-popup-blocking", true),
-		chromedp.Flag("disable-prompt-on-repost", true),
-		chromedp.Flag("disable-sync", true),
-		chromedp.Flag("metrics-recording-only", true),
-		chromedp.Flag("safebrowsing-disable-auto-update", true),
-		chromedp.Flag("disable-extensions", true),
-		chromedp.Flag("disable-dev-shm-usage", true),
-		chromedp.Flag("ignore-certificate-errors", cfg.Insecure),
-	)
-
-	if cfg.Proxy != "" {
-		allocatorOpts = append(allocatorOpts, chromedp.ProxyServer(cfg.Proxy))
+	pool := getPool(cfg)
+	if err := pool.acquire(ctx); err != nil {
+		return Page{}, err
 	}
+	defer pool.release()
 
-	if execPath, ok := findExecPath(); ok {
-		allocatorOpts = append(allocatorOpts, chromedp.ExecPath(execPath))
+	page, crashed, err := renderInPool(ctx, pool, proxies, rawURL, cfg)
+	if crashed && ctx.Err() == nil {
+		// The browser went away mid-render; the next call relaunches it.
+		page, _, err = renderInPool(ctx, pool, proxies, rawURL, cfg)
 	}
+	return page, err
+}
 
-	allocCtx, cancelAlloc := chromedp.NewExecAllocator(ctx, allocatorOpts...)
-	defer cancelAlloc()
+// renderInPool renders rawURL in a new tab of the pooled browser. crashed reports
+// whether the browser process died while rendering.
+func renderInPool(ctx context.Context, pool *browserPool, proxies *proxy.Pool, rawURL string, cfg config.Config) (page Page, crashed bool, err error) {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultRenderTimeout
+	}
 
-	browserCtx, cancelBrowser := chromedp.NewContext(allocCtx)
-	defer cancelBrowser()
+	steps, err := LoadInteractions(cfg.Interactions)
+	if err != nil {
+		return Page{}, false, err
+	}
 
-	headers := network.Headers{
-		"User-Agent":      "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/121.0.0.0 Safari/537.36",
-		"Accept":          "text/html,application/xhtml+xml,application/xml;q=0.9,image/webp,*/*;q=0.8",
-		"Accept-Language": "en-US,en;q=0.8",
-		"Accept-Encoding": "gzip, deflate, br",
+	globals, err := globalsRegexp(cfg)
+	if err != nil {
+		return Page{}, false, err
 	}
 
-	if cfg.Cookies != "" {
-		headers["Cookie"] = cfg.Cookies
+	t, err := openTab(ctx, pool, proxies, cfg, timeout, interactionBudget(steps, cfg.AutoInteract))
+	if err != nil {
+		return Page{}, false, err
 	}
+	defer t.close()
 
 	quiet := defaultQuietPeriod
 	if timeout > 0 && timeout < quiet {
@@ -88,19 +98,208 @@ func FetchRendered(ctx context.Context, rawURL string, cfg config.Config) (strin
 	}
 
 	tracker := newNetworkIdleTracker(quiet)
+	recorder := newRequestRecorder(rawURL)
 
-	var html string
-	err := chromedp.Run(browserCtx,
-		tracker.actionAttach(),
-		network.Enable(),
-		network.SetExtraHTTPHeaders(headers),
+	actions := []chromedp.Action{tracker.actionAttach(), recorder.actionAttach()}
+	actions = append(actions, t.setup...)
+	actions = append(actions,
 		chromedp.Navigate(rawURL),
 		chromedp.WaitReady("body", chromedp.ByQuery),
 		tracker.waitAction(timeout),
-		chromedp.OuterHTML("html", &html, chromedp.ByQuery),
 	)
 
-	return html, err
+	if len(steps) > 0 {
+		actions = append(actions, interactionsAction(steps, tracker))
+	}
+
+	var snapshots []string
+	if cfg.AutoInteract > 0 {
+		actions = append(actions, autoInteractAction(cfg, rawURL, tracker, &snapshots))
+	}
+
+	actions = append(actions,
+		chromedp.OuterHTML("html", &page.HTML, chromedp.ByQuery),
+		captureState(globals, &page.State),
+		recorder.collectScripts(&page.Scripts),
+	)
+
+	crashed, err = t.run(proxies, actions...)
+
+	page.Requests = recorder.Requests()
+	if len(snapshots) > 0 {
+		// Routes visited by automatic interaction are kept alongside the final DOM.
+		page.HTML = strings.Join(append([]string{page.HTML}, snapshots...), "\n")
+	}
+
+	return page, crashed, err
+}
+
+// tab is a browser tab prepared with the proxy, TLS, header and session settings of a run.
+type tab struct {
+	ctx        context.Context
+	browserCtx context.Context
+	proxy      *url.URL
+	// setup must run before the tab navigates anywhere.
+	setup []chromedp.Action
+	close func()
+}
+
+// openTab opens a tab in the pooled browser bounded by the caller's ctx and by timeout
+// plus extra, the time reserved for interacting with the page once it loaded.
+func openTab(ctx context.Context, pool *browserPool, proxies *proxy.Pool, cfg config.Config, timeout, extra time.Duration) (*tab, error) {
+	browserCtx, err := pool.browser()
+	if err != nil {
+		return nil, err
+	}
+
+	selectedProxy := proxies.Next()
+
+	// Client certificates, custom CAs and DNS servers cannot be handed to Chromium on the
+	// command line, so requests are fulfilled through a Go client carrying them instead.
+	var interceptClient *http.Client
+	if needsInterception(cfg) {
+		interceptClient, err = newInterceptClient(cfg, selectedProxy, timeout)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var proxyServer string
+	var proxyUser *url.Userinfo
+	if selectedProxy != nil && interceptClient == nil {
+		proxyServer, proxyUser, err = chromiumProxy(selectedProxy)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tabCtx, cancelTab := pool.newTab(browserCtx, proxyServer)
+
+	// The tab lives under the browser context, so tie it to the caller explicitly.
+	stop := context.AfterFunc(ctx, cancelTab)
+
+	tabCtx, cancelTimeout := context.WithTimeout(tabCtx, timeout+extra)
+
+	var setup []chromedp.Action
+	switch {
+	case interceptClient != nil:
+		setup = append(setup, interceptAction(interceptClient))
+	case proxyUser != nil:
+		setup = append(setup, proxyAuthAction(proxyUser))
+	}
+
+	headers, userAgent := browserHeaders(cfg)
+	setup = append(setup,
+		network.Enable(),
+		emulation.SetUserAgentOverride(userAgent),
+		network.SetExtraHTTPHeaders(headers),
+	)
+	if cfg.Session != nil {
+		setup = append(setup, sessionAction(cfg.Session))
+	}
+
+	return &tab{
+		ctx:        tabCtx,
+		browserCtx: browserCtx,
+		proxy:      selectedProxy,
+		setup:      setup,
+		close: func() {
+			stop()
+			cancelTimeout()
+			cancelTab()
+		},
+	}, nil
+}
+
+// run executes actions in the tab, benching the proxy it used when the proxy failed.
+// crashed reports whether the browser process died meanwhile.
+func (t *tab) run(proxies *proxy.Pool, actions ...chromedp.Action) (crashed bool, err error) {
+	err = chromedp.Run(t.ctx, actions...)
+	if err != nil && t.proxy != nil && proxy.IsProxyError(err) {
+		proxies.MarkFailed(t.proxy)
+	}
+	return err != nil && t.browserCtx.Err() != nil, err
+}
+
+// unsupportedHeaders lists headers Chromium manages itself and refuses to take
+// from Network.setExtraHTTPHeaders.
+var unsupportedHeaders = map[string]struct{}{
+	"Connection":        {},
+	"Content-Length":    {},
+	"Host":              {},
+	"Keep-Alive":        {},
+	"Proxy-Connection":  {},
+	"Te":                {},
+	"Trailer":           {},
+	"Transfer-Encoding": {},
+	"Upgrade":           {},
+}
+
+// defaultBrowserUserAgent is the User-Agent of the headless browser when none is
+// configured. It stays close to the Chromium it runs in, whereas the HTTP fetcher keeps
+// config.DefaultUserAgent.
+const defaultBrowserUserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/121.0.0.0 Safari/537.36"
+
+// browserHeaders splits the configured request headers into the extra headers sent
+// by the browser and the User-Agent, which is applied as an override so that
+// navigator.userAgent matches the header.
+func browserHeaders(cfg config.Config) (network.Headers, string) {
+	headers := network.Headers{}
+	userAgent := defaultBrowserUserAgent
+
+	for _, header := range cfg.RequestHeaders() {
+		if header.Name == "User-Agent" {
+			if header.Value != config.DefaultUserAgent {
+				userAgent = header.Value
+			}
+			continue
+		}
+		if _, skip := unsupportedHeaders[header.Name]; skip {
+			continue
+		}
+		headers[header.Name] = header.Value
+	}
+
+	return headers, userAgent
+}
+
+// HeaderMismatch is a request header the HTTP fetcher and the headless browser send
+// differently. An empty value means the header is not sent on that path.
+type HeaderMismatch struct {
+	Name    string
+	HTTP    string
+	Browser string
+}
+
+// HeaderMismatches returns the configured headers that the HTTP fetcher and the
+// headless browser do not send alike.
+func HeaderMismatches(cfg config.Config) []HeaderMismatch {
+	headers, userAgent := browserHeaders(cfg)
+
+	var mismatches []HeaderMismatch
+	for _, header := range cfg.RequestHeaders() {
+		browserValue, _ := headers[header.Name].(string)
+		if header.Name == "User-Agent" {
+			browserValue = userAgent
+		}
+		if browserValue != header.Value {
+			mismatches = append(mismatches, HeaderMismatch{Name: header.Name, HTTP: header.Value, Browser: browserValue})
+		}
+	}
+	return mismatches
+}
+
+// hostResolverRules translates --resolve overrides into Chromium's --host-resolver-rules syntax.
+func hostResolverRules(rules []config.ResolveRule) string {
+	parts := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		address := rule.Address
+		if strings.Contains(address, ":") {
+			address = "[" + address + "]"
+		}
+		parts = append(parts, fmt.Sprintf("MAP %s:%s %s", rule.Host, rule.Port, address))
+	}
+	return strings.Join(parts, ", ")
 }
 
 // IsAvailable returns true when a supported Chromium based browser can be located.
@@ -0,0 +1,161 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+
+	"github.com/chromedp/chromedp"
+
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/config"
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/model"
+)
+
+const (
+	// maxGlobals bounds how many window globals are serialised per page.
+	maxGlobals = 200
+	// maxStateValue bounds the size of a single serialised value.
+	maxStateValue = 256 * 1024
+)
+
+// storageDumpScript returns the page origin and the content of both web storages.
+const storageDumpScript = `(() => {
+	const read = (store) => {
+		const out = {};
+		try {
+			for (let i = 0; i < store.length; i++) {
+				const key = store.key(i);
+				out[key] = store.getItem(key);
+			}
+		} catch (e) {}
+		return out;
+	};
+	return {origin: location.origin, local: read(localStorage), session: read(sessionStorage)};
+})()`
+
+type storageDump struct {
+	Origin  string            `json:"origin"`
+	Local   map[string]string `json:"local"`
+	Session map[string]string `json:"session"`
+}
+
+// pageGlobalsScript lists the globals the page defined itself, by comparing window
+// with the pristine window of a blank iframe.
+const pageGlobalsScript = `(() => {
+	const frame = document.createElement('iframe');
+	frame.style.display = 'none';
+	document.documentElement.appendChild(frame);
+	const builtin = new Set(Object.getOwnPropertyNames(frame.contentWindow));
+	frame.remove();
+	return Object.getOwnPropertyNames(window).filter((name) => !builtin.has(name));
+})()`
+
+// serializeGlobalsScript JSON encodes the named globals, dropping functions, DOM nodes
+// and cycles, and skipping values larger than the limit.
+const serializeGlobalsScript = `((names, limit) => {
+	const out = {};
+	for (const name of names) {
+		try {
+			const seen = new WeakSet();
+			const value = JSON.stringify(window[name], (key, v) => {
+				if (typeof v === 'function') { return undefined; }
+				if (v && typeof v === 'object') {
+					if (typeof Node !== 'undefined' && v instanceof Node) { return undefined; }
+					if (seen.has(v)) { return undefined; }
+					seen.add(v);
+				}
+				return v;
+			});
+			if (value !== undefined && value.length <= limit) { out[name] = value; }
+		} catch (e) {}
+	}
+	return out;
+})(%s, %d)`
+
+var (
+	globalsMu      sync.Mutex
+	globalsSource  string
+	globalsPattern *regexp.Regexp
+)
+
+// globalsRegexp returns the compiled --globals pattern, caching it across renders.
+func globalsRegexp(cfg config.Config) (*regexp.Regexp, error) {
+	source := cfg.GlobalsPattern
+	if source == "" {
+		source = config.DefaultGlobalsPattern
+	}
+
+	globalsMu.Lock()
+	defer globalsMu.Unlock()
+
+	if globalsPattern != nil && globalsSource == source {
+		return globalsPattern, nil
+	}
+
+	pattern, err := regexp.Compile(source)
+	if err != nil {
+		return nil, fmt.Errorf("invalid globals pattern: %w", err)
+	}
+	globalsSource, globalsPattern = source, pattern
+	return pattern, nil
+}
+
+// captureState records the window globals whose names match pattern and the page's
+// local and session storage.
+func captureState(pattern *regexp.Regexp, out *[]model.StateEntry) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		var names []string
+		if err := chromedp.Evaluate(pageGlobalsScript, &names).Do(ctx); err != nil {
+			return err
+		}
+
+		var selected []string
+		for _, name := range names {
+			if pattern.MatchString(name) {
+				selected = append(selected, name)
+				if len(selected) == maxGlobals {
+					break
+				}
+			}
+		}
+
+		if len(selected) > 0 {
+			encoded, _ := json.Marshal(selected)
+			var values map[string]string
+			script := fmt.Sprintf(serializeGlobalsScript, encoded, maxStateValue)
+			if err := chromedp.Evaluate(script, &values).Do(ctx); err != nil {
+				return err
+			}
+			*out = append(*out, stateEntries("window", values)...)
+		}
+
+		var storage storageDump
+		if err := chromedp.Evaluate(storageDumpScript, &storage).Do(ctx); err != nil {
+			return err
+		}
+		*out = append(*out, stateEntries("localStorage", storage.Local)...)
+		*out = append(*out, stateEntries("sessionStorage", storage.Session)...)
+		return nil
+	})
+}
+
+func stateEntries(source string, values map[string]string) []model.StateEntry {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	entries := make([]model.StateEntry, 0, len(names))
+	for _, name := range names {
+		value := values[name]
+		if len(value) > maxStateValue {
+			value = value[:maxStateValue]
+		}
+		entries = append(entries, model.StateEntry{Source: source, Name: name, Value: value})
+	}
+	return entries
+}
@@ -0,0 +1,53 @@
+package browser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/config"
+)
+
+func TestStateEntries(t *testing.T) {
+	values := map[string]string{
+		"token": "abc",
+		"big":   strings.Repeat("x", maxStateValue+10),
+		"api":   `{"base":"/v1"}`,
+	}
+
+	entries := stateEntries("localStorage", values)
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+
+	names := []string{entries[0].Name, entries[1].Name, entries[2].Name}
+	if strings.Join(names, ",") != "api,big,token" {
+		t.Fatalf("expected entries sorted by name, got %v", names)
+	}
+	for _, entry := range entries {
+		if entry.Source != "localStorage" {
+			t.Fatalf("unexpected source %q", entry.Source)
+		}
+	}
+	if len(entries[1].Value) != maxStateValue {
+		t.Fatalf("expected large value truncated to %d bytes, got %d", maxStateValue, len(entries[1].Value))
+	}
+}
+
+func TestGlobalsRegexp(t *testing.T) {
+	pattern, err := globalsRegexp(config.Config{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, name := range []string{"__NEXT_DATA__", "appConfig", "ENV", "initialState"} {
+		if !pattern.MatchString(name) {
+			t.Fatalf("expected default pattern to match %q", name)
+		}
+	}
+	if pattern.MatchString("jQuery") {
+		t.Fatalf("expected default pattern to skip library globals")
+	}
+
+	if _, err := globalsRegexp(config.Config{GlobalsPattern: "("}); err == nil {
+		t.Fatalf("expected invalid pattern to fail")
+	}
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"flag"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -158,6 +159,255 @@ func TestParseFlagsHeaderInvalidFormat(t *testing.T) {
 	}
 }
 
+func TestParseFlagsRedirects(t *testing.T) {
+	oldArgs := os.Args
+	t.Cleanup(func() {
+		os.Args = oldArgs
+	})
+
+	oldCommandLine := flag.CommandLine
+	t.Cleanup(func() {
+		flag.CommandLine = oldCommandLine
+	})
+
+	flag.CommandLine = flag.NewFlagSet(oldArgs[0], flag.ContinueOnError)
+
+	os.Args = []string{
+		oldArgs[0],
+		"-i", "https://example.com",
+		"--redirects", "scope",
+		"--max-redirects", "3",
+	}
+
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() returned error: %v", err)
+	}
+
+	if cfg.Redirects != RedirectScope {
+		t.Fatalf("expected redirect policy %q, got %q", RedirectScope, cfg.Redirects)
+	}
+
+	if cfg.MaxRedirects != 3 {
+		t.Fatalf("expected max redirects 3, got %d", cfg.MaxRedirects)
+	}
+
+	flag.CommandLine = flag.NewFlagSet(oldArgs[0], flag.ContinueOnError)
+	os.Args = []string{oldArgs[0], "-i", "https://example.com", "--redirects", "sometimes"}
+
+	if _, err := ParseFlags(); err == nil {
+		t.Fatal("expected error due to invalid redirect policy, got nil")
+	}
+}
+
+func TestParseFlagsResolve(t *testing.T) {
+	oldArgs := os.Args
+	t.Cleanup(func() {
+		os.Args = oldArgs
+	})
+
+	oldCommandLine := flag.CommandLine
+	t.Cleanup(func() {
+		flag.CommandLine = oldCommandLine
+	})
+
+	flag.CommandLine = flag.NewFlagSet(oldArgs[0], flag.ContinueOnError)
+
+	os.Args = []string{
+		oldArgs[0],
+		"-i", "https://example.com",
+		"--resolve", "Example.com:443:10.0.0.5",
+		"--resolve", "api.example.com:8443:[2001:db8::1]",
+		"--dns-server", "10.0.0.53",
+	}
+
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() returned error: %v", err)
+	}
+
+	if len(cfg.Resolve) != 2 {
+		t.Fatalf("expected 2 resolve rules, got %d", len(cfg.Resolve))
+	}
+
+	if got, want := cfg.Resolve[0], (ResolveRule{Host: "example.com", Port: "443", Address: "10.0.0.5"}); got != want {
+		t.Fatalf("unexpected first rule: %+v", got)
+	}
+
+	if got, want := cfg.Resolve[1], (ResolveRule{Host: "api.example.com", Port: "8443", Address: "2001:db8::1"}); got != want {
+		t.Fatalf("unexpected second rule: %+v", got)
+	}
+
+	if cfg.DNSServer != "10.0.0.53:53" {
+		t.Fatalf("expected DNS server to default to port 53, got %q", cfg.DNSServer)
+	}
+
+	for _, invalid := range []string{"example.com:443", "example.com:http:10.0.0.5", "example.com:443:not-an-ip"} {
+		flag.CommandLine = flag.NewFlagSet(oldArgs[0], flag.ContinueOnError)
+		os.Args = []string{oldArgs[0], "-i", "https://example.com", "--resolve", invalid}
+
+		if _, err := ParseFlags(); err == nil {
+			t.Fatalf("expected error for resolve entry %q, got nil", invalid)
+		}
+	}
+}
+
+func TestParseFlagsInteractionsRequireRender(t *testing.T) {
+	oldArgs := os.Args
+	t.Cleanup(func() {
+		os.Args = oldArgs
+	})
+
+	oldCommandLine := flag.CommandLine
+	t.Cleanup(func() {
+		flag.CommandLine = oldCommandLine
+	})
+
+	cases := [][]string{
+		{"--interactions", "steps.yaml"},
+		{"--auto-interact", "5"},
+	}
+	for _, extra := range cases {
+		flag.CommandLine = flag.NewFlagSet(oldArgs[0], flag.ContinueOnError)
+		os.Args = append([]string{oldArgs[0], "-i", "https://example.com"}, extra...)
+		if _, err := ParseFlags(); err == nil {
+			t.Fatalf("expected %v without --render to fail", extra)
+		}
+	}
+
+	flag.CommandLine = flag.NewFlagSet(oldArgs[0], flag.ContinueOnError)
+	os.Args = []string{oldArgs[0], "-i", "https://example.com", "--render", "--interactions", "steps.yaml", "--auto-interact", "5"}
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Interactions != "steps.yaml" || cfg.AutoInteract != 5 {
+		t.Fatalf("unexpected interaction settings: %q %d", cfg.Interactions, cfg.AutoInteract)
+	}
+}
+
+func TestParseFlagsGlobalsPattern(t *testing.T) {
+	oldArgs := os.Args
+	t.Cleanup(func() {
+		os.Args = oldArgs
+	})
+
+	oldCommandLine := flag.CommandLine
+	t.Cleanup(func() {
+		flag.CommandLine = oldCommandLine
+	})
+
+	flag.CommandLine = flag.NewFlagSet(oldArgs[0], flag.ContinueOnError)
+	os.Args = []string{oldArgs[0], "-i", "https://example.com", "--render"}
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.GlobalsPattern != DefaultGlobalsPattern {
+		t.Fatalf("expected default globals pattern, got %q", cfg.GlobalsPattern)
+	}
+
+	flag.CommandLine = flag.NewFlagSet(oldArgs[0], flag.ContinueOnError)
+	os.Args = []string{oldArgs[0], "-i", "https://example.com", "--render", "--globals", "("}
+	if _, err := ParseFlags(); err == nil {
+		t.Fatalf("expected invalid --globals pattern to fail")
+	}
+}
+
+func TestParseFlagsCaptureInputs(t *testing.T) {
+	oldArgs := os.Args
+	t.Cleanup(func() {
+		os.Args = oldArgs
+	})
+
+	oldCommandLine := flag.CommandLine
+	t.Cleanup(func() {
+		flag.CommandLine = oldCommandLine
+	})
+
+	flag.CommandLine = flag.NewFlagSet(oldArgs[0], flag.ContinueOnError)
+	os.Args = []string{oldArgs[0], "-i", "capture.har"}
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(cfg.MIMETypes, ",") != DefaultMIMETypes {
+		t.Fatalf("expected default MIME types, got %v", cfg.MIMETypes)
+	}
+
+	flag.CommandLine = flag.NewFlagSet(oldArgs[0], flag.ContinueOnError)
+	os.Args = []string{oldArgs[0], "-i", "capture.json", "--har", "--mime", " JavaScript, ,JSON "}
+	cfg, err = ParseFlags()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.HAR || strings.Join(cfg.MIMETypes, ",") != "javascript,json" {
+		t.Fatalf("unexpected capture settings: %v %v", cfg.HAR, cfg.MIMETypes)
+	}
+
+	flag.CommandLine = flag.NewFlagSet(oldArgs[0], flag.ContinueOnError)
+	os.Args = []string{oldArgs[0], "-i", "capture.har", "--mime", "all"}
+	cfg, err = ParseFlags()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.MIMETypes != nil {
+		t.Fatalf("expected 'all' to disable MIME filtering, got %v", cfg.MIMETypes)
+	}
+
+	flag.CommandLine = flag.NewFlagSet(oldArgs[0], flag.ContinueOnError)
+	os.Args = []string{oldArgs[0], "-i", "capture.har", "--har", "--burp"}
+	if _, err := ParseFlags(); err == nil {
+		t.Fatalf("expected --burp and --har together to fail")
+	}
+
+	flag.CommandLine = flag.NewFlagSet(oldArgs[0], flag.ContinueOnError)
+	os.Args = []string{oldArgs[0], "-i", "session.flow", "--mitmproxy", "--zap"}
+	if _, err := ParseFlags(); err == nil {
+		t.Fatalf("expected --mitmproxy and --zap together to fail")
+	}
+}
+
+func TestRequestHeaders(t *testing.T) {
+	cfg := Config{
+		UserAgent: "Scanner/1.0",
+		Cookies:   "session=abc",
+		Headers: []Header{
+			{Name: "Authorization", Value: "Bearer token"},
+			{Name: "Accept-Language", Value: "es-ES"},
+		},
+	}
+
+	got := map[string]string{}
+	for _, header := range cfg.RequestHeaders() {
+		if _, dup := got[header.Name]; dup {
+			t.Fatalf("header %s returned more than once", header.Name)
+		}
+		got[header.Name] = header.Value
+	}
+
+	want := map[string]string{
+		"User-Agent":      "Scanner/1.0",
+		"Cookie":          "session=abc",
+		"Authorization":   "Bearer token",
+		"Accept-Language": "es-ES",
+	}
+	for name, value := range want {
+		if got[name] != value {
+			t.Fatalf("expected %s to be %q, got %q", name, value, got[name])
+		}
+	}
+
+	if got["Accept"] == "" || got["Accept-Encoding"] == "" {
+		t.Fatalf("expected default headers to be present, got %v", got)
+	}
+
+	defaults := Config{}.RequestHeaders()
+	if defaults[0].Name != "User-Agent" || defaults[0].Value != DefaultUserAgent {
+		t.Fatalf("expected default User-Agent, got %+v", defaults[0])
+	}
+}
+
 func findOutput(outputs []OutputTarget, format OutputFormat) (OutputTarget, bool) {
 	for _, target := range outputs {
 		if target.Format == format {
@@ -166,3 +416,158 @@ func findOutput(outputs []OutputTarget, format OutputFormat) (OutputTarget, bool
 	}
 	return OutputTarget{}, false
 }
+
+func TestParseFlagsDirectoryOptions(t *testing.T) {
+	oldArgs := os.Args
+	t.Cleanup(func() {
+		os.Args = oldArgs
+	})
+
+	oldCommandLine := flag.CommandLine
+	t.Cleanup(func() {
+		flag.CommandLine = oldCommandLine
+	})
+
+	flag.CommandLine = flag.NewFlagSet(oldArgs[0], flag.ContinueOnError)
+	os.Args = []string{oldArgs[0], "-i", "build"}
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.MaxFileSize != 10<<20 || cfg.Include != nil || cfg.Exclude != nil {
+		t.Fatalf("unexpected defaults: %d %v %v", cfg.MaxFileSize, cfg.Include, cfg.Exclude)
+	}
+
+	flag.CommandLine = flag.NewFlagSet(oldArgs[0], flag.ContinueOnError)
+	os.Args = []string{oldArgs[0], "-i", "build", "--include", "*.js, *.mjs", "--include", "*.html", "--exclude", "vendor/", "--max-file-size", "512kb"}
+	cfg, err = ParseFlags()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(cfg.Include, ",") != "*.js,*.mjs,*.html" || strings.Join(cfg.Exclude, ",") != "vendor/" {
+		t.Fatalf("unexpected patterns: %v %v", cfg.Include, cfg.Exclude)
+	}
+	if cfg.MaxFileSize != 512<<10 {
+		t.Fatalf("expected 512KB limit, got %d", cfg.MaxFileSize)
+	}
+
+	flag.CommandLine = flag.NewFlagSet(oldArgs[0], flag.ContinueOnError)
+	os.Args = []string{oldArgs[0], "-i", "build", "--max-file-size", "ten"}
+	if _, err := ParseFlags(); err == nil {
+		t.Fatalf("expected invalid --max-file-size to fail")
+	}
+}
+
+func TestParseFlagsStdinContent(t *testing.T) {
+	oldArgs := os.Args
+	t.Cleanup(func() {
+		os.Args = oldArgs
+	})
+
+	oldCommandLine := flag.CommandLine
+	t.Cleanup(func() {
+		flag.CommandLine = oldCommandLine
+	})
+
+	flag.CommandLine = flag.NewFlagSet(oldArgs[0], flag.ContinueOnError)
+	os.Args = []string{oldArgs[0], "--stdin-content", "--base", "https://example.com/app.js"}
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.StdinContent || cfg.Input != "-" || cfg.Base != "https://example.com/app.js" {
+		t.Fatalf("unexpected stdin settings: %v %q %q", cfg.StdinContent, cfg.Input, cfg.Base)
+	}
+
+	invalid := [][]string{
+		{"--stdin-content", "-i", "targets.txt"},
+		{"-i", "app.js", "--base", "https://example.com/"},
+		{"--stdin-content", "--base", "/static/app.js"},
+		{"--stdin-content", "--base", "ftp://example.com/app.js"},
+	}
+	for _, args := range invalid {
+		flag.CommandLine = flag.NewFlagSet(oldArgs[0], flag.ContinueOnError)
+		os.Args = append([]string{oldArgs[0]}, args...)
+		if _, err := ParseFlags(); err == nil {
+			t.Fatalf("expected %v to fail", args)
+		}
+	}
+}
+
+func TestParseFlagsSnapshotSelection(t *testing.T) {
+	oldArgs := os.Args
+	t.Cleanup(func() {
+		os.Args = oldArgs
+	})
+
+	oldCommandLine := flag.CommandLine
+	t.Cleanup(func() {
+		flag.CommandLine = oldCommandLine
+	})
+
+	flag.CommandLine = flag.NewFlagSet(oldArgs[0], flag.ContinueOnError)
+	os.Args = []string{oldArgs[0], "-i", "site.cdx", "--snapshots", "all", "--from", "2019", "--to", "20210630", "--snapshot-source", "site.warc.gz"}
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.AllSnapshots || cfg.SnapshotsFrom != "2019" || cfg.SnapshotsTo != "20210630" || cfg.SnapshotSource != "site.warc.gz" {
+		t.Fatalf("unexpected snapshot settings: %+v", cfg)
+	}
+
+	invalid := [][]string{
+		{"--snapshots", "first"},
+		{"--from", "2019-01-01"},
+		{"--to", "202101011200001"},
+		{"--from", "2021", "--to", "2019"},
+		{"--cdx", "--har"},
+	}
+	for _, args := range invalid {
+		flag.CommandLine = flag.NewFlagSet(oldArgs[0], flag.ContinueOnError)
+		os.Args = append([]string{oldArgs[0], "-i", "site.cdx"}, args...)
+		if _, err := ParseFlags(); err == nil {
+			t.Fatalf("expected %v to fail", args)
+		}
+	}
+}
+
+func TestParseFlagsSecrets(t *testing.T) {
+	oldArgs := os.Args
+	t.Cleanup(func() {
+		os.Args = oldArgs
+	})
+
+	oldCommandLine := flag.CommandLine
+	t.Cleanup(func() {
+		flag.CommandLine = oldCommandLine
+	})
+
+	flag.CommandLine = flag.NewFlagSet(oldArgs[0], flag.ContinueOnError)
+	os.Args = []string{oldArgs[0], "-i", "https://example.com", "--gf", "jwt", "--secrets", "github-token, stripe-secret-key,github-token"}
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.SecretsAll || len(cfg.SecretRules) != 2 || cfg.SecretRules[0] != "github-token" || cfg.SecretRules[1] != "stripe-secret-key" {
+		t.Fatalf("unexpected secrets rules: %+v", cfg.SecretRules)
+	}
+	if len(cfg.GFPatterns) != 1 || cfg.GFPatterns[0] != "jwt" {
+		t.Fatalf("expected gf rules to be kept, got %v", cfg.GFPatterns)
+	}
+
+	flag.CommandLine = flag.NewFlagSet(oldArgs[0], flag.ContinueOnError)
+	os.Args = []string{oldArgs[0], "-i", "https://example.com", "--secrets", "ALL"}
+	cfg, err = ParseFlags()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.SecretsAll {
+		t.Fatalf("expected all secrets rules to be selected")
+	}
+
+	flag.CommandLine = flag.NewFlagSet(oldArgs[0], flag.ContinueOnError)
+	os.Args = []string{oldArgs[0], "-i", "https://example.com", "--secrets", " , "}
+	if _, err := ParseFlags(); err == nil {
+		t.Fatalf("expected an empty rule list to fail")
+	}
+}
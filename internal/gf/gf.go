@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -13,10 +14,33 @@ import (
 	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/output"
 )
 
-// Definition represents a gf rule loaded from disk.
+// contextRadius bounds how much of the matched line is kept on each side of a content
+// match, since minified resources often fit on a single line.
+const contextRadius = 80
+
+// MatchTarget selects what a rule is matched against.
+type MatchTarget int
+
+const (
+	// MatchLink matches a rule against the extracted endpoints.
+	MatchLink MatchTarget = iota
+	// MatchContent matches a rule against the full content of every resource.
+	MatchContent
+)
+
+// keywordWindow is how far before or after a match a rule's keywords are looked for.
+const keywordWindow = 64
+
+// Definition represents a gf rule loaded from disk or from the built-in secrets ruleset.
 type Definition struct {
 	Name     string
 	Patterns []*regexp.Regexp
+	Match    MatchTarget
+	// MinEntropy is the Shannon entropy, in bits per byte, the secret must reach. The
+	// secret is the first capture group of the pattern, or the whole match.
+	MinEntropy float64
+	// Keywords, when set, must appear in or near the match (case-insensitive).
+	Keywords []string
 }
 
 // Finding captures a gf match extracted from the reports.
@@ -130,21 +154,31 @@ type gfFile struct {
 	Pattern  string   `json:"pattern"`
 	Patterns []string `json:"patterns"`
 	Flags    string   `json:"flags"`
+	// Match is "content" (the default) or "link".
+	Match string `json:"match"`
+	// Entropy and Keywords filter out matches that are unlikely to be real secrets.
+	Entropy  float64  `json:"entropy"`
+	Keywords []string `json:"keywords"`
 }
 
 func parseDefinition(path string) (Definition, error) {
-	var def Definition
-
 	content, err := os.ReadFile(path)
 	if err != nil {
-		return def, err
+		return Definition{}, err
 	}
 
 	var file gfFile
 	if err := json.Unmarshal(content, &file); err != nil {
-		return def, err
+		return Definition{}, err
 	}
 
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return compileDefinition(name, file)
+}
+
+func compileDefinition(name string, file gfFile) (Definition, error) {
+	var def Definition
+
 	var rawPatterns []string
 	if file.Pattern != "" {
 		rawPatterns = append(rawPatterns, file.Pattern)
@@ -170,67 +204,207 @@ func parseDefinition(path string) (Definition, error) {
 		compiled = append(compiled, re)
 	}
 
-	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	match := MatchContent
+	switch strings.ToLower(strings.TrimSpace(file.Match)) {
+	case "", "content":
+	case "link", "links":
+		match = MatchLink
+	default:
+		return def, fmt.Errorf("invalid match target %q (expected \"content\" or \"link\")", file.Match)
+	}
+
+	if file.Entropy < 0 || file.Entropy > 8 {
+		return def, fmt.Errorf("invalid entropy %v (expected 0 to 8 bits per byte)", file.Entropy)
+	}
 
-	return Definition{Name: name, Patterns: compiled}, nil
+	var keywords []string
+	for _, keyword := range file.Keywords {
+		if keyword = strings.ToLower(strings.TrimSpace(keyword)); keyword != "" {
+			keywords = append(keywords, keyword)
+		}
+	}
+
+	return Definition{
+		Name:       name,
+		Patterns:   compiled,
+		Match:      match,
+		MinEntropy: file.Entropy,
+		Keywords:   keywords,
+	}, nil
 }
 
-// FindInReports runs all gf definitions against the collected reports and returns any matches found.
+// FindInReports runs the link rules against the endpoints of the collected reports, and
+// every rule against the runtime state captured from rendered pages.
 func FindInReports(reports []output.ResourceReport, defs []Definition) []Finding {
 	if len(defs) == 0 {
 		return nil
 	}
 
-	type key struct {
-		resource string
-		line     int
-		evidence string
+	set := make(findingSet)
+	scan := func(resource string, line int, text, context string, match func(Definition) bool) {
+		for _, def := range defs {
+			if !match(def) {
+				continue
+			}
+			for _, loc := range def.find(text) {
+				set.add(resource, line, text[loc[0]:loc[1]], context, def.Name)
+			}
+		}
 	}
-
-	findingsByKey := make(map[key]*Finding)
+	linkRules := func(def Definition) bool { return def.Match == MatchLink }
+	allRules := func(Definition) bool { return true }
 
 	for _, report := range reports {
 		for _, ep := range report.Endpoints {
-			context := strings.TrimSpace(ep.Context)
-
-			for _, def := range defs {
-				for _, re := range def.Patterns {
-					matches := re.FindAllString(ep.Link, -1)
-					if matches == nil {
-						continue
-					}
-
-					for _, match := range matches {
-						k := key{resource: report.Resource, line: ep.Line, evidence: match}
-
-						finding, ok := findingsByKey[k]
-						if !ok {
-							finding = &Finding{
-								Resource: report.Resource,
-								Line:     ep.Line,
-								Evidence: match,
-								Context:  context,
-							}
-							findingsByKey[k] = finding
-						} else if finding.Context == "" && context != "" {
-							finding.Context = context
-						}
-
-						if !containsRule(finding.Rules, def.Name) {
-							finding.Rules = append(finding.Rules, def.Name)
-						}
-					}
-				}
+			scan(report.Resource, ep.Line, ep.Link, strings.TrimSpace(ep.Context), linkRules)
+		}
+		// Runtime state is not part of the fetched content, so content rules run on it
+		// too. It has no line; its context names the global or storage item.
+		for _, entry := range report.State {
+			scan(report.Resource, 0, entry.Value, entry.Source+"."+entry.Name, allRules)
+		}
+	}
+
+	return set.sorted()
+}
+
+// FindInContent runs the content rules against the full content of a resource. Lines
+// are numbered from 1 and the context is the matched line, shortened around the match.
+func FindInContent(resource, content string, defs []Definition) []Finding {
+	set := make(findingSet)
+	var newlines []int
+	for _, def := range defs {
+		if def.Match != MatchContent {
+			continue
+		}
+		for _, loc := range def.find(content) {
+			if newlines == nil {
+				newlines = newlineOffsets(content)
+			}
+			line := sort.SearchInts(newlines, loc[0]) + 1
+			set.add(resource, line, content[loc[0]:loc[1]], lineContext(content, loc[0], loc[1]), def.Name)
+		}
+	}
+	return set.sorted()
+}
+
+// find returns the submatch indexes of the non-empty matches of the rule in text that
+// pass its entropy and keyword checks.
+func (d Definition) find(text string) [][]int {
+	var matches [][]int
+	for _, re := range d.Patterns {
+		for _, loc := range re.FindAllStringSubmatchIndex(text, -1) {
+			if loc[0] == loc[1] || !d.accepts(text, loc) {
+				continue
+			}
+			matches = append(matches, loc)
+		}
+	}
+	return matches
+}
+
+func (d Definition) accepts(text string, loc []int) bool {
+	if d.MinEntropy > 0 {
+		secret := text[loc[0]:loc[1]]
+		if len(loc) >= 4 && loc[2] >= 0 {
+			secret = text[loc[2]:loc[3]]
+		}
+		if shannonEntropy(secret) < d.MinEntropy {
+			return false
+		}
+	}
+
+	if len(d.Keywords) > 0 {
+		window := strings.ToLower(text[max(0, loc[0]-keywordWindow):min(len(text), loc[1]+keywordWindow)])
+		found := false
+		for _, keyword := range d.Keywords {
+			if strings.Contains(window, keyword) {
+				found = true
+				break
 			}
 		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// shannonEntropy returns the Shannon entropy of value in bits per byte.
+func shannonEntropy(value string) float64 {
+	if value == "" {
+		return 0
+	}
+
+	var counts [256]int
+	for idx := 0; idx < len(value); idx++ {
+		counts[value[idx]]++
+	}
+
+	entropy := 0.0
+	total := float64(len(value))
+	for _, count := range counts {
+		if count == 0 {
+			continue
+		}
+		p := float64(count) / total
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// Merge combines findings from several scans, merging those of the same resource, line
+// and evidence.
+func Merge(groups ...[]Finding) []Finding {
+	set := make(findingSet)
+	for _, findings := range groups {
+		for _, finding := range findings {
+			for _, rule := range finding.Rules {
+				set.add(finding.Resource, finding.Line, finding.Evidence, finding.Context, rule)
+			}
+		}
+	}
+	return set.sorted()
+}
+
+type findingKey struct {
+	resource string
+	line     int
+	evidence string
+}
+
+// findingSet merges matches of the same evidence on the same line of a resource.
+type findingSet map[findingKey]*Finding
+
+func (s findingSet) add(resource string, line int, evidence, context, rule string) {
+	k := findingKey{resource: resource, line: line, evidence: evidence}
+
+	finding, ok := s[k]
+	if !ok {
+		finding = &Finding{
+			Resource: resource,
+			Line:     line,
+			Evidence: evidence,
+			Context:  context,
+		}
+		s[k] = finding
+	} else if finding.Context == "" && context != "" {
+		finding.Context = context
 	}
 
-	if len(findingsByKey) == 0 {
+	if !containsRule(finding.Rules, rule) {
+		finding.Rules = append(finding.Rules, rule)
+	}
+}
+
+func (s findingSet) sorted() []Finding {
+	if len(s) == 0 {
 		return nil
 	}
 
-	findings := make([]Finding, 0, len(findingsByKey))
-	for _, finding := range findingsByKey {
+	findings := make([]Finding, 0, len(s))
+	for _, finding := range s {
 		sort.Strings(finding.Rules)
 		findings = append(findings, *finding)
 	}
@@ -251,6 +425,38 @@ func FindInReports(reports []output.ResourceReport, defs []Definition) []Finding
 	return findings
 }
 
+// newlineOffsets returns the offsets of the line breaks of content.
+func newlineOffsets(content string) []int {
+	offsets := []int{}
+	for idx := 0; idx < len(content); idx++ {
+		if content[idx] == '\n' {
+			offsets = append(offsets, idx)
+		}
+	}
+	return offsets
+}
+
+// lineContext returns the line holding content[start:end], keeping at most
+// contextRadius bytes on each side of the match.
+func lineContext(content string, start, end int) string {
+	lineStart := strings.LastIndexByte(content[:start], '\n') + 1
+	lineEnd := len(content)
+	if idx := strings.IndexByte(content[end:], '\n'); idx != -1 {
+		lineEnd = end + idx
+	}
+
+	prefix, suffix := "", ""
+	if start-lineStart > contextRadius {
+		lineStart = start - contextRadius
+		prefix = "..."
+	}
+	if lineEnd-end > contextRadius {
+		lineEnd = end + contextRadius
+		suffix = "..."
+	}
+	return prefix + strings.TrimSpace(strings.ToValidUTF8(content[lineStart:lineEnd], "")) + suffix
+}
+
 func containsRule(rules []string, rule string) bool {
 	for _, existing := range rules {
 		if existing == rule {
@@ -264,7 +470,9 @@ func containsRule(rules []string, rule string) bool {
 func RuleNames(defs []Definition) []string {
 	names := make([]string, 0, len(defs))
 	for _, def := range defs {
-		names = append(names, def.Name)
+		if !containsRule(names, def.Name) {
+			names = append(names, def.Name)
+		}
 	}
 	sort.Strings(names)
 	return names
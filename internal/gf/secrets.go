@@ -0,0 +1,96 @@
+package gf
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// secretsRuleset is the built-in secrets ruleset. Its version changes whenever a rule
+// is added or tuned, and is recorded in the report metadata of runs that use it.
+//
+//go:embed secrets.json
+var secretsRuleset []byte
+
+type secretsFile struct {
+	Version string       `json:"version"`
+	Rules   []secretRule `json:"rules"`
+}
+
+type secretRule struct {
+	Name string `json:"name"`
+	gfFile
+}
+
+// SecretsVersion returns the version of the built-in secrets ruleset.
+func SecretsVersion() string {
+	file, err := readSecretsFile()
+	if err != nil {
+		return ""
+	}
+	return file.Version
+}
+
+// LoadSecrets compiles the named rules of the built-in secrets ruleset, or all of them
+// when useAll is set. Secrets are searched for in the full content of every resource.
+func LoadSecrets(names []string, useAll bool) ([]Definition, error) {
+	file, err := readSecretsFile()
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[strings.ToLower(strings.TrimSpace(name))] = false
+	}
+
+	var definitions []Definition
+	for _, rule := range file.Rules {
+		if !useAll {
+			if _, ok := wanted[rule.Name]; !ok {
+				continue
+			}
+			wanted[rule.Name] = true
+		}
+
+		def, err := compileDefinition(rule.Name, rule.gfFile)
+		if err != nil {
+			return nil, fmt.Errorf("built-in secrets rule %s: %w", rule.Name, err)
+		}
+		definitions = append(definitions, def)
+	}
+
+	var missing []string
+	for name, found := range wanted {
+		if !found {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("unknown secrets rule %s (available: %s)", strings.Join(missing, ", "), strings.Join(secretRuleNames(file), ", "))
+	}
+
+	sort.Slice(definitions, func(i, j int) bool { return definitions[i].Name < definitions[j].Name })
+
+	return definitions, nil
+}
+
+func readSecretsFile() (secretsFile, error) {
+	var file secretsFile
+	if err := json.Unmarshal(secretsRuleset, &file); err != nil {
+		return file, fmt.Errorf("unable to parse built-in secrets ruleset: %w", err)
+	}
+	return file, nil
+}
+
+func secretRuleNames(file secretsFile) []string {
+	names := make([]string, 0, len(file.Rules))
+	for _, rule := range file.Rules {
+		names = append(names, rule.Name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,189 @@
+// Package imports recognises the scripts that pages and bundles load at runtime
+// without referencing them as plain script tags: service workers, web workers,
+// importScripts, dynamic import() calls and import maps.
+package imports
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Import kinds.
+const (
+	KindServiceWorker = "service-worker"
+	KindWorker        = "worker"
+	KindSharedWorker  = "shared-worker"
+	KindImportScripts = "import-scripts"
+	KindDynamicImport = "dynamic-import"
+	KindImportMap     = "importmap"
+)
+
+// Import is a script loaded at runtime.
+type Import struct {
+	Kind string
+	URL  string
+}
+
+var (
+	serviceWorker = regexp.MustCompile("\\bserviceWorker\\s*\\.\\s*register\\(\\s*(?:new\\s+URL\\(\\s*)?([\"'`])([^\"'`]+)[\"'`]")
+	worker        = regexp.MustCompile("\\bnew\\s+(Shared)?Worker\\(\\s*(?:new\\s+URL\\(\\s*)?([\"'`])([^\"'`]+)[\"'`]")
+	importScripts = regexp.MustCompile(`\bimportScripts\(([^)]*)\)`)
+	dynamicImport = regexp.MustCompile("(?:^|[^\\w.$])import\\(\\s*([\"'`])([^\"'`]+)[\"'`]\\s*[,)]")
+	quoted        = regexp.MustCompile("[\"'`]([^\"'`]+)[\"'`]")
+	importMap     = regexp.MustCompile(`(?is)<script[^>]*\btype\s*=\s*["']?importmap["']?[^>]*>(.*?)</script>`)
+)
+
+type importMapDocument struct {
+	Imports map[string]string            `json:"imports"`
+	Scopes  map[string]map[string]string `json:"scopes"`
+}
+
+// Find returns the scripts that content loads at runtime, in order of appearance. Each
+// URL is reported once, with the kind of its first reference.
+// Bare module specifiers are resolved through the import maps declared in content and
+// dropped when no map covers them.
+func Find(content string) []Import {
+	type located struct {
+		Import
+		offset int
+	}
+
+	var (
+		found []located
+		seen  = make(map[string]struct{})
+	)
+	add := func(kind, url string, offset int) {
+		url = strings.TrimSpace(url)
+		if url == "" || strings.Contains(url, "${") || !isURLSpecifier(url) {
+			return
+		}
+		if _, ok := seen[url]; ok {
+			return
+		}
+		seen[url] = struct{}{}
+		found = append(found, located{Import: Import{Kind: kind, URL: url}, offset: offset})
+	}
+
+	mappings := make(map[string]string)
+	for _, m := range importMap.FindAllStringSubmatchIndex(content, -1) {
+		var doc importMapDocument
+		if err := json.Unmarshal([]byte(content[m[2]:m[3]]), &doc); err != nil {
+			continue
+		}
+		for _, specifiers := range append([]map[string]string{doc.Imports}, scopeMaps(doc.Scopes)...) {
+			for _, specifier := range sortedKeys(specifiers) {
+				target := specifiers[specifier]
+				if _, ok := mappings[specifier]; !ok {
+					mappings[specifier] = target
+				}
+				// Prefix mappings ("lib/": "/js/lib/") name directories, not scripts.
+				if !strings.HasSuffix(target, "/") {
+					add(KindImportMap, target, m[0])
+				}
+			}
+		}
+	}
+
+	for _, m := range serviceWorker.FindAllStringSubmatchIndex(content, -1) {
+		add(KindServiceWorker, content[m[4]:m[5]], m[0])
+	}
+
+	for _, m := range worker.FindAllStringSubmatchIndex(content, -1) {
+		kind := KindWorker
+		if m[2] != -1 {
+			kind = KindSharedWorker
+		}
+		add(kind, content[m[6]:m[7]], m[0])
+	}
+
+	for _, m := range importScripts.FindAllStringSubmatchIndex(content, -1) {
+		args := content[m[2]:m[3]]
+		for _, arg := range quoted.FindAllStringSubmatch(args, -1) {
+			add(KindImportScripts, arg[1], m[0])
+		}
+	}
+
+	for _, m := range dynamicImport.FindAllStringSubmatchIndex(content, -1) {
+		specifier := content[m[4]:m[5]]
+		if !isURLSpecifier(specifier) {
+			mapped, ok := resolveBare(specifier, mappings)
+			if !ok {
+				continue
+			}
+			specifier = mapped
+		}
+		add(KindDynamicImport, specifier, m[0])
+	}
+
+	sort.SliceStable(found, func(i, j int) bool { return found[i].offset < found[j].offset })
+
+	imports := make([]Import, len(found))
+	for idx, entry := range found {
+		imports[idx] = entry.Import
+	}
+	return imports
+}
+
+// URLs returns the script URLs referenced by imports, without duplicates.
+func URLs(imports []Import) []string {
+	seen := make(map[string]struct{}, len(imports))
+	var urls []string
+	for _, imp := range imports {
+		if _, ok := seen[imp.URL]; ok {
+			continue
+		}
+		seen[imp.URL] = struct{}{}
+		urls = append(urls, imp.URL)
+	}
+	return urls
+}
+
+// isURLSpecifier reports whether specifier is a URL or a relative path rather than a
+// bare module name that only an import map can resolve.
+func isURLSpecifier(specifier string) bool {
+	switch {
+	case strings.HasPrefix(specifier, "/"),
+		strings.HasPrefix(specifier, "./"),
+		strings.HasPrefix(specifier, "../"):
+		return true
+	}
+	lower := strings.ToLower(specifier)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")
+}
+
+// resolveBare maps a bare specifier through exact or the longest prefix mapping.
+func resolveBare(specifier string, mappings map[string]string) (string, bool) {
+	if target, ok := mappings[specifier]; ok {
+		return target, true
+	}
+
+	best := ""
+	for prefix := range mappings {
+		if strings.HasSuffix(prefix, "/") && strings.HasPrefix(specifier, prefix) && len(prefix) > len(best) {
+			best = prefix
+		}
+	}
+	if best == "" || !strings.HasSuffix(mappings[best], "/") {
+		return "", false
+	}
+	return mappings[best] + strings.TrimPrefix(specifier, best), true
+}
+
+func scopeMaps(scopes map[string]map[string]string) []map[string]string {
+	out := make([]map[string]string, 0, len(scopes))
+	for _, scope := range sortedKeys(scopes) {
+		out = append(out, scopes[scope])
+	}
+	return out
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
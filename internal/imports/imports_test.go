@@ -0,0 +1,89 @@
+package imports
+
+import (
+	"reflect"
+	"testing"
+)
+
+const importMapPage = `<script type="importmap">
+{
+  "imports": {
+    "app": "/static/app.mjs",
+    "ui/": "/static/ui/",
+    "react": "https://esm.sh/react@18"
+  },
+  "scopes": {
+    "/legacy/": {"app": "/legacy/app.js"}
+  }
+}
+</script>
+<script type="module">
+import("app").then(m => m.start());
+import("ui/dialog.js");
+import("unmapped");
+</script>`
+
+func TestFind(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		want    []Import
+	}{
+		{
+			name: "workers",
+			content: `if ("serviceWorker" in navigator) { navigator.serviceWorker.register("/sw", {scope: "/"}); }
+const w = new Worker(new URL("./workers/parse.worker.js", import.meta.url), {type: "module"});
+const shared = new SharedWorker('/shared/bus');
+const dynamic = new Worker(URL.createObjectURL(blob));`,
+			want: []Import{
+				{Kind: KindServiceWorker, URL: "/sw"},
+				{Kind: KindWorker, URL: "./workers/parse.worker.js"},
+				{Kind: KindSharedWorker, URL: "/shared/bus"},
+			},
+		},
+		{
+			name: "importScripts",
+			content: `self.importScripts("/vendor/workbox-sw.js", 'precache-manifest.3f2a.js');
+importScripts(` + "`https://cdn.example.com/analytics`" + `);`,
+			want: []Import{
+				{Kind: KindImportScripts, URL: "/vendor/workbox-sw.js"},
+				{Kind: KindImportScripts, URL: "https://cdn.example.com/analytics"},
+			},
+		},
+		{
+			name: "dynamic imports",
+			content: `const admin = () => import("./admin/index");
+const locale = import(` + "`./locales/${lang}.js`" + `);
+loader.import("./ignored.js");
+import("lodash-es");`,
+			want: []Import{
+				{Kind: KindDynamicImport, URL: "./admin/index"},
+			},
+		},
+		{
+			name:    "import map",
+			content: importMapPage,
+			want: []Import{
+				{Kind: KindImportMap, URL: "/static/app.mjs"},
+				{Kind: KindImportMap, URL: "https://esm.sh/react@18"},
+				{Kind: KindImportMap, URL: "/legacy/app.js"},
+				{Kind: KindDynamicImport, URL: "/static/ui/dialog.js"},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Find(tc.content); !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestURLsDropsDuplicates(t *testing.T) {
+	found := append(Find(importMapPage), Import{Kind: KindDynamicImport, URL: "/static/app.mjs"})
+	if urls := URLs(found); len(urls) != 4 {
+		t.Fatalf("expected URLs to drop duplicates, got %v", urls)
+	}
+}
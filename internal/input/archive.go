@@ -0,0 +1,238 @@
+package input
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/model"
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/parser"
+)
+
+const (
+	// maxArchiveDepth bounds how many archives nested inside each other are opened.
+	maxArchiveDepth = 3
+	// maxArchiveEntrySize bounds the decompressed size of a single archive entry, which
+	// protects against decompression bombs.
+	maxArchiveEntrySize = 64 << 20
+)
+
+// archiveFormat identifies how an archive is unpacked.
+type archiveFormat int
+
+const (
+	archiveNone archiveFormat = iota
+	archiveZip
+	archiveTar
+	archiveTarGzip
+	archiveTarBzip2
+	archiveGzip
+	archiveCRX
+	archiveASAR
+)
+
+// archiveFormatOf detects the format of an archive from its file name.
+func archiveFormatOf(name string) archiveFormat {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".zip"), isZipPackage(lower):
+		return archiveZip
+	case strings.HasSuffix(lower, ".crx"):
+		return archiveCRX
+	case strings.HasSuffix(lower, ".asar"):
+		return archiveASAR
+	case strings.HasSuffix(lower, ".tar"):
+		return archiveTar
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return archiveTarGzip
+	case strings.HasSuffix(lower, ".tar.bz2"), strings.HasSuffix(lower, ".tbz2"):
+		return archiveTarBzip2
+	case strings.HasSuffix(lower, ".gz"):
+		return archiveGzip
+	default:
+		return archiveNone
+	}
+}
+
+// isArchiveFile reports whether path names an archive whose entries can be analysed.
+func isArchiveFile(path string) bool {
+	return archiveFormatOf(path) != archiveNone && !isWARCFile(path)
+}
+
+// analysableEntry reports whether an archive entry holds a script, HTML or JSON file.
+func analysableEntry(name string) bool {
+	lower := strings.ToLower(name)
+	if parser.ScriptExtensionRegex().MatchString(lower) || isAppBundle(lower) {
+		return true
+	}
+	switch path.Ext(lower) {
+	case ".html", ".htm", ".json":
+		return true
+	}
+	return false
+}
+
+// parseArchiveFile unpacks the archive at path in memory and returns its script, HTML
+// and JSON entries as prefetched targets named "archive.zip!/path/in/archive".
+// Archives nested inside it are opened too, up to maxArchiveDepth levels.
+func parseArchiveFile(path string) ([]model.Target, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []model.Target
+	err = walkArchive(path, data, archiveFormatOf(path), 1, func(name string, content []byte) {
+		targets = append(targets, model.Target{URL: name, Content: string(content), Prefetched: true})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("%s: archive contains no script, HTML or JSON files", path)
+	}
+	return targets, nil
+}
+
+// walkArchive calls emit for every analysable entry of the archive named name, opening
+// nested archives while depth allows.
+func walkArchive(name string, data []byte, format archiveFormat, depth int, emit func(string, []byte)) error {
+	visit := func(entry string, content []byte) error {
+		entry = strings.TrimPrefix(path.Clean("/"+entry), "/")
+		qualified := name + "!/" + entry
+
+		if nested := archiveFormatOf(entry); nested != archiveNone {
+			if depth >= maxArchiveDepth {
+				return nil
+			}
+			// A broken nested archive should not hide the rest of the outer one.
+			_ = walkArchive(qualified, content, nested, depth+1, emit)
+			return nil
+		}
+
+		if analysableEntry(entry) && !isHermesBytecode(content) {
+			emit(qualified, content)
+		}
+		return nil
+	}
+
+	switch format {
+	case archiveZip:
+		return walkZip(data, visit)
+	case archiveCRX:
+		payload, err := crxPayload(data)
+		if err != nil {
+			return err
+		}
+		return walkZip(payload, visit)
+	case archiveASAR:
+		return walkASAR(data, visit)
+	case archiveTar:
+		return walkTar(bytes.NewReader(data), visit)
+	case archiveTarGzip:
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		return walkTar(gz, visit)
+	case archiveTarBzip2:
+		return walkTar(bzip2.NewReader(bytes.NewReader(data)), visit)
+	case archiveGzip:
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		content, err := readArchiveEntry(gz)
+		if err != nil {
+			return err
+		}
+		// A single compressed file is named after the archive without its .gz suffix.
+		entry := gz.Name
+		if entry == "" {
+			entry = strings.TrimSuffix(path.Base(strings.ReplaceAll(name, "\\", "/")), path.Ext(name))
+		}
+		return visit(entry, content)
+	default:
+		return errors.New("unsupported archive format")
+	}
+}
+
+func walkZip(data []byte, visit func(string, []byte) error) error {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return err
+	}
+
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		if !analysableEntry(file.Name) && archiveFormatOf(file.Name) == archiveNone {
+			continue
+		}
+
+		rc, err := file.Open()
+		if err != nil {
+			continue
+		}
+		content, err := readArchiveEntry(rc)
+		rc.Close()
+		if err != nil {
+			continue
+		}
+
+		if err := visit(file.Name, content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func walkTar(r io.Reader, visit func(string, []byte) error) error {
+	reader := tar.NewReader(r)
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if !analysableEntry(header.Name) && archiveFormatOf(header.Name) == archiveNone {
+			continue
+		}
+
+		content, err := readArchiveEntry(reader)
+		if err != nil {
+			continue
+		}
+		if err := visit(header.Name, content); err != nil {
+			return err
+		}
+	}
+}
+
+// readArchiveEntry reads an entry, refusing entries larger than maxArchiveEntrySize.
+func readArchiveEntry(r io.Reader) ([]byte, error) {
+	content, err := io.ReadAll(io.LimitReader(r, maxArchiveEntrySize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(content) > maxArchiveEntrySize {
+		return nil, fmt.Errorf("entry exceeds %d bytes", maxArchiveEntrySize)
+	}
+	return content, nil
+}
@@ -0,0 +1,133 @@
+package input
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/config"
+)
+
+func zipBytes(t *testing.T, files map[string][]byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatalf("create zip entry: %v", err)
+		}
+		f.Write(content)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("close zip: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func tarGzipBytes(t *testing.T, files map[string][]byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	w := tar.NewWriter(gz)
+	for name, content := range files {
+		if err := w.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatalf("write tar header: %v", err)
+		}
+		w.Write(content)
+	}
+	w.Close()
+	gz.Close()
+	return buf.Bytes()
+}
+
+func TestResolveTargetsArchive(t *testing.T) {
+	dir := t.TempDir()
+
+	// Only maxArchiveDepth archives are opened, so level4.zip is skipped.
+	level4 := zipBytes(t, map[string][]byte{"too-deep.js": []byte("x")})
+	level3 := zipBytes(t, map[string][]byte{"level3.js": []byte("var l = 3"), "level4.zip": level4})
+	nested := tarGzipBytes(t, map[string][]byte{
+		"package/lib/client.js": []byte(`fetch("/api/nested")`),
+		"package/level3.zip":    level3,
+	})
+	archive := zipBytes(t, map[string][]byte{
+		"static/js/main.js": []byte(`fetch("/api/main")`),
+		"index.html":        []byte(`<a href="/admin">admin</a>`),
+		"manifest.json":     []byte(`{"start_url":"/app"}`),
+		"static/logo.png":   []byte("\x89PNG"),
+		"vendor/sdk.tar.gz": nested,
+		"../escape/evil.js": []byte("var a = 1"),
+		"static/media/dir/": nil,
+	})
+
+	path := filepath.Join(dir, "dist.zip")
+	if err := os.WriteFile(path, archive, 0o644); err != nil {
+		t.Fatalf("write archive: %v", err)
+	}
+
+	targets, err := ResolveTargets(config.Config{Input: path})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := make(map[string]string, len(targets))
+	for _, target := range targets {
+		if !target.Prefetched {
+			t.Fatalf("archive targets should be prefetched: %+v", target)
+		}
+		got[target.URL] = target.Content
+	}
+
+	want := map[string]string{
+		path + "!/static/js/main.js":                                `fetch("/api/main")`,
+		path + "!/index.html":                                       `<a href="/admin">admin</a>`,
+		path + "!/manifest.json":                                    `{"start_url":"/app"}`,
+		path + "!/vendor/sdk.tar.gz!/package/lib/client.js":         `fetch("/api/nested")`,
+		path + "!/escape/evil.js":                                   "var a = 1",
+		path + "!/vendor/sdk.tar.gz!/package/level3.zip!/level3.js": "var l = 3",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d targets, got %v", len(want), got)
+	}
+	for name, content := range want {
+		if got[name] != content {
+			t.Fatalf("target %s = %q, want %q (all: %v)", name, got[name], content, got)
+		}
+	}
+}
+
+func TestResolveTargetsGzipFile(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write([]byte(`fetch("/api/gz")`))
+	gz.Close()
+
+	path := filepath.Join(t.TempDir(), "bundle.js.gz")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatalf("write gzip file: %v", err)
+	}
+
+	targets, err := ResolveTargets(config.Config{Input: path})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(targets) != 1 || targets[0].URL != path+"!/bundle.js" || targets[0].Content != `fetch("/api/gz")` {
+		t.Fatalf("unexpected targets: %+v", targets)
+	}
+}
+
+func TestResolveTargetsArchiveWithoutScripts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "images.zip")
+	if err := os.WriteFile(path, zipBytes(t, map[string][]byte{"logo.png": []byte("\x89PNG")}), 0o644); err != nil {
+		t.Fatalf("write archive: %v", err)
+	}
+
+	if _, err := ResolveTargets(config.Config{Input: path}); err == nil {
+		t.Fatalf("expected an archive without analysable entries to fail")
+	}
+}
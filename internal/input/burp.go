@@ -0,0 +1,148 @@
+package input
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/model"
+)
+
+// warningOut receives the warnings about capture entries that could not be imported.
+var warningOut io.Writer = os.Stderr
+
+type burpData struct {
+	Base64 string `xml:"base64,attr"`
+	Text   string `xml:",chardata"`
+}
+
+// decode returns the raw bytes of a request or response. Burp marks base64 encoded
+// data with base64="true"; older exports omit the attribute and always encode.
+func (d burpData) decode() ([]byte, error) {
+	if strings.EqualFold(strings.TrimSpace(d.Base64), "false") {
+		return []byte(d.Text), nil
+	}
+	return base64.StdEncoding.DecodeString(strings.TrimSpace(d.Text))
+}
+
+type burpItem struct {
+	URL      string   `xml:"url"`
+	Method   string   `xml:"method"`
+	Status   string   `xml:"status"`
+	MIMEType string   `xml:"mimetype"`
+	Response burpData `xml:"response"`
+}
+
+// openBurpFile reads a Burp Suite XML export item by item. Items that cannot be
+// decoded are skipped with a warning, and a truncated export ends the stream once the
+// items before the damage were read.
+func openBurpFile(path string, mimeTypes []string) (*Stream, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		seen    = newTargetDedup()
+		decoder = xml.NewDecoder(file)
+		index   int
+		read    bool
+	)
+	next := func() (model.Target, error) {
+		for {
+			token, err := decoder.Token()
+			if errors.Is(err, io.EOF) {
+				return model.Target{}, io.EOF
+			}
+			if err != nil {
+				if !read {
+					return model.Target{}, fmt.Errorf("invalid Burp XML file: %w", err)
+				}
+				fmt.Fprintf(warningOut, "Warning: %s: stopped reading after item %d: %v\n", path, index, err)
+				return model.Target{}, io.EOF
+			}
+
+			start, ok := token.(xml.StartElement)
+			if !ok || start.Name.Local != "item" {
+				continue
+			}
+			index++
+
+			var item burpItem
+			if err := decoder.DecodeElement(&item, &start); err != nil {
+				fmt.Fprintf(warningOut, "Warning: skipping Burp item %d: %v\n", index, err)
+				continue
+			}
+
+			target, err := burpTarget(item)
+			if err != nil {
+				fmt.Fprintf(warningOut, "Warning: skipping Burp item %d (%s): %v\n", index, item.URL, err)
+				continue
+			}
+			read = true
+			if strings.TrimSpace(target.Content) == "" || !mimeAllowed(burpFilterType(target.MIMEType), target.URL, mimeTypes) {
+				continue
+			}
+			if seen.addCapture(target) {
+				return target, nil
+			}
+		}
+	}
+
+	return newRecordStream(next, file), nil
+}
+
+// burpFilterType returns the MIME type a target is filtered by. Without response
+// headers, only Burp's own short type names such as "script" or "JSON" are known, so
+// the URL extension decides instead.
+func burpFilterType(mimeType string) string {
+	if !strings.Contains(mimeType, "/") {
+		return ""
+	}
+	return mimeType
+}
+
+// burpTarget converts an item into a target. The response headers are stripped from
+// the body; responses that are not HTTP messages are analysed as they are.
+func burpTarget(item burpItem) (model.Target, error) {
+	url := strings.TrimSpace(item.URL)
+	if url == "" {
+		return model.Target{}, errors.New("item has no URL")
+	}
+
+	raw, err := item.Response.decode()
+	if err != nil {
+		return model.Target{}, fmt.Errorf("invalid base64 response: %w", err)
+	}
+
+	target := model.Target{
+		URL:        url,
+		Content:    string(raw),
+		Prefetched: true,
+		Method:     strings.TrimSpace(item.Method),
+		MIMEType:   strings.TrimSpace(item.MIMEType),
+	}
+	if status, err := strconv.Atoi(strings.TrimSpace(item.Status)); err == nil {
+		target.Status = status
+	}
+
+	if bytes.HasPrefix(raw, []byte("HTTP/")) {
+		if resp, err := readCapturedResponse(bytes.NewReader(raw)); err == nil {
+			target.Content = string(resp.Body)
+			if resp.Status != 0 {
+				target.Status = resp.Status
+			}
+			if resp.MIMEType != "" {
+				target.MIMEType = resp.MIMEType
+			}
+		}
+	}
+
+	return target, nil
+}
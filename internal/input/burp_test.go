@@ -0,0 +1,161 @@
+package input
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/config"
+)
+
+func TestResolveTargetsBurp(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "burp.xml")
+
+	body := base64.StdEncoding.EncodeToString([]byte("console.log('test');"))
+	data := `<items><item><url>https://example.com/app.js</url><response>` + body + `</response></item></items>`
+	writeFile(t, file, []byte(data))
+
+	cfg := config.Config{Input: file, Burp: true}
+	targets, err := ResolveTargets(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(targets) != 1 {
+		t.Fatalf("expected 1 target, got %d", len(targets))
+	}
+	if !targets[0].Prefetched {
+		t.Fatalf("burp targets should be prefetched")
+	}
+	if targets[0].Content != "console.log('test');" {
+		t.Fatalf("unexpected prefetched content %q", targets[0].Content)
+	}
+}
+
+func TestResolveTargetsBurpExport(t *testing.T) {
+	encoded := func(s string) string {
+		return base64.StdEncoding.EncodeToString([]byte(s))
+	}
+	response := "HTTP/2 200 OK\r\nContent-Type: application/javascript\r\nContent-Length: 19\r\n\r\nfetch('/api/users')"
+
+	data := `<?xml version="1.0"?>
+<!DOCTYPE items [
+<!ELEMENT items (item*)>
+]>
+<items burpVersion="2024.1">
+  <item>
+    <url><![CDATA[https://example.com/app.js]]></url>
+    <method><![CDATA[GET]]></method>
+    <request base64="true">` + encoded("GET /app.js HTTP/2\r\n\r\n") + `</request>
+    <status>200</status>
+    <mimetype>script</mimetype>
+    <response base64="true"><![CDATA[` + encoded(response) + `]]></response>
+  </item>
+  <item>
+    <url><![CDATA[https://example.com/broken.js]]></url>
+    <method><![CDATA[GET]]></method>
+    <response base64="true">%%%not-base64%%%</response>
+  </item>
+  <item>
+    <url><![CDATA[https://example.com/api/config]]></url>
+    <method><![CDATA[POST]]></method>
+    <status>201</status>
+    <mimetype>JSON</mimetype>
+    <response base64="false"><![CDATA[HTTP/1.1 201 Created
+Content-Type: application/json
+
+{"next": "/api/next"}]]></response>
+  </item>
+  <item>
+    <url><![CDATA[https://example.com/empty]]></url>
+    <method><![CDATA[GET]]></method>
+    <response base64="true"></response>
+  </item>
+</items>`
+
+	file := filepath.Join(t.TempDir(), "history.xml")
+	writeFile(t, file, []byte(data))
+
+	var warnings bytes.Buffer
+	oldOut := warningOut
+	warningOut = &warnings
+	t.Cleanup(func() {
+		warningOut = oldOut
+	})
+
+	targets, err := ResolveTargets(config.Config{Input: file, Burp: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(targets) != 2 {
+		t.Fatalf("expected 2 targets, got %+v", targets)
+	}
+
+	script := targets[0]
+	if script.URL != "https://example.com/app.js" || script.Content != "fetch('/api/users')" {
+		t.Fatalf("unexpected script target %+v", script)
+	}
+	if script.Method != "GET" || script.Status != 200 || script.MIMEType != "application/javascript" {
+		t.Fatalf("unexpected script metadata %+v", script)
+	}
+
+	api := targets[1]
+	if strings.TrimSpace(api.Content) != `{"next": "/api/next"}` {
+		t.Fatalf("expected headers to be stripped from plain response, got %q", api.Content)
+	}
+	if api.Method != "POST" || api.Status != 201 || api.MIMEType != "application/json" {
+		t.Fatalf("unexpected api metadata %+v", api)
+	}
+
+	if !strings.Contains(warnings.String(), "skipping Burp item 2 (https://example.com/broken.js)") {
+		t.Fatalf("expected a warning for the broken item, got %q", warnings.String())
+	}
+}
+
+func TestResolveTargetsBurpInvalid(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "broken.xml")
+	writeFile(t, file, []byte("<items><item><url>"))
+
+	oldOut := warningOut
+	warningOut = io.Discard
+	t.Cleanup(func() {
+		warningOut = oldOut
+	})
+
+	if _, err := ResolveTargets(config.Config{Input: file, Burp: true}); err == nil {
+		t.Fatalf("expected truncated export without items to fail")
+	}
+}
+
+func TestResolveTargetsBurpMIMEFilter(t *testing.T) {
+	encoded := func(s string) string {
+		return base64.StdEncoding.EncodeToString([]byte(s))
+	}
+	item := func(url, mimetype, response string) string {
+		return `<item><url>` + url + `</url><method>GET</method><mimetype>` + mimetype + `</mimetype><response base64="true">` + encoded(response) + `</response></item>`
+	}
+
+	data := `<items>` +
+		item("https://example.com/app.js", "script", "HTTP/1.1 200 OK\r\nContent-Type: application/javascript\r\n\r\nfetch('/api/users')") +
+		item("https://example.com/logo.png", "image", "HTTP/1.1 200 OK\r\nContent-Type: image/png\r\n\r\n\x89PNG") +
+		item("https://example.com/site.css", "CSS", "body{background:url(/bg.png)}") +
+		item("https://example.com/raw.js", "script", "fetch('/api/raw')") +
+		`</items>`
+	file := filepath.Join(t.TempDir(), "burp.xml")
+	writeFile(t, file, []byte(data))
+
+	targets, err := ResolveTargets(config.Config{Input: file, Burp: true, MIMETypes: []string{"javascript"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var urls []string
+	for _, target := range targets {
+		urls = append(urls, target.URL)
+	}
+	if strings.Join(urls, ",") != "https://example.com/app.js,https://example.com/raw.js" {
+		t.Fatalf("expected only the scripts to be imported, got %v", urls)
+	}
+}
@@ -0,0 +1,458 @@
+package input
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/config"
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/model"
+)
+
+// waybackLayout is the layout of the 14 digit timestamps used by web archives.
+const waybackLayout = "20060102150405"
+
+// cdxSnapshot is a capture listed in a CDX index.
+type cdxSnapshot struct {
+	Timestamp string
+	Original  string
+	MIMEType  string
+	Status    string
+	Digest    string
+}
+
+// cdxDefaultFields is the field order of CDX lines without a header, as returned by the
+// Wayback CDX server; cdx11Fields is the order of pywb and OpenWayback CDX11 files.
+var (
+	cdxDefaultFields = []string{"urlkey", "timestamp", "original", "mimetype", "statuscode", "digest", "length"}
+	cdx11Fields      = []string{"urlkey", "timestamp", "original", "mimetype", "statuscode", "digest", "redirect", "robotflags", "length", "offset", "filename"}
+)
+
+// cdxHeaderFields maps the letters of a " CDX N b a m s k ..." header to field names.
+var cdxHeaderFields = map[string]string{
+	"N": "urlkey", "A": "urlkey", "b": "timestamp", "a": "original",
+	"m": "mimetype", "s": "statuscode", "k": "digest",
+}
+
+// isCDXFile reports whether path names a CDX or CDXJ index.
+func isCDXFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".cdx", ".cdxj":
+		return true
+	}
+	return false
+}
+
+// newSnapshot builds a snapshot from named fields, accepting the names used by the CDX
+// server, pywb and CDXJ files.
+func newSnapshot(fields map[string]string) cdxSnapshot {
+	pick := func(names ...string) string {
+		for _, name := range names {
+			if value := strings.TrimSpace(fields[name]); value != "" && value != "-" {
+				return value
+			}
+		}
+		return ""
+	}
+	return cdxSnapshot{
+		Timestamp: pick("timestamp"),
+		Original:  pick("original", "url"),
+		MIMEType:  pick("mimetype", "mime"),
+		Status:    pick("statuscode", "status"),
+		Digest:    pick("digest"),
+	}
+}
+
+// readCDX reads the snapshots of a CDX index: plain CDX lines, with or without a " CDX"
+// header, CDXJ lines, JSON lines, or the JSON array returned by the CDX server.
+func readCDX(r io.Reader, visit func(cdxSnapshot)) error {
+	reader := bufio.NewReader(r)
+	if first, err := peekNonSpace(reader); err == nil && first == '[' {
+		return readCDXJSONArray(reader, visit)
+	}
+
+	fields := cdxDefaultFields
+	headerSeen := false
+	for lineNumber := 1; ; lineNumber++ {
+		line, err := reader.ReadString('\n')
+		if trimmed := strings.TrimSpace(line); trimmed != "" {
+			switch {
+			case strings.HasPrefix(strings.TrimLeft(line, " "), "CDX "):
+				fields, headerSeen = nil, true
+				for _, letter := range strings.Fields(trimmed)[1:] {
+					fields = append(fields, cdxHeaderFields[letter])
+				}
+			case strings.HasPrefix(trimmed, "{"):
+				var object map[string]any
+				if err := json.Unmarshal([]byte(trimmed), &object); err != nil {
+					return fmt.Errorf("line %d: %w", lineNumber, err)
+				}
+				visit(newSnapshot(stringFields(object)))
+			default:
+				parts := strings.Fields(trimmed)
+				if len(parts) >= 3 && strings.HasPrefix(parts[2], "{") {
+					// CDXJ: "urlkey timestamp {json}".
+					var object map[string]any
+					if err := json.Unmarshal([]byte(strings.Join(parts[2:], " ")), &object); err != nil {
+						return fmt.Errorf("line %d: %w", lineNumber, err)
+					}
+					named := stringFields(object)
+					named["timestamp"] = parts[1]
+					visit(newSnapshot(named))
+					break
+				}
+
+				layout := fields
+				if !headerSeen && len(parts) == len(cdx11Fields) {
+					layout = cdx11Fields
+				}
+				named := make(map[string]string, len(layout))
+				for idx, name := range layout {
+					if idx < len(parts) && name != "" {
+						named[name] = parts[idx]
+					}
+				}
+				visit(newSnapshot(named))
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// readCDXJSONArray reads the CDX server's JSON output: an array of rows whose first row
+// names the fields.
+func readCDXJSONArray(r io.Reader, visit func(cdxSnapshot)) error {
+	decoder := json.NewDecoder(r)
+	if _, err := decoder.Token(); err != nil {
+		return err
+	}
+
+	var header []string
+	for decoder.More() {
+		var row []string
+		if err := decoder.Decode(&row); err != nil {
+			return err
+		}
+		if header == nil {
+			header = row
+			continue
+		}
+		named := make(map[string]string, len(header))
+		for idx, name := range header {
+			if idx < len(row) {
+				named[name] = row[idx]
+			}
+		}
+		visit(newSnapshot(named))
+	}
+	return nil
+}
+
+func stringFields(object map[string]any) map[string]string {
+	fields := make(map[string]string, len(object))
+	for key, value := range object {
+		switch v := value.(type) {
+		case string:
+			fields[key] = v
+		case float64:
+			fields[key] = strconv.FormatFloat(v, 'f', -1, 64)
+		}
+	}
+	return fields
+}
+
+func peekNonSpace(r *bufio.Reader) (byte, error) {
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		if b != ' ' && b != '\t' && b != '\r' && b != '\n' {
+			return b, r.UnreadByte()
+		}
+	}
+}
+
+// snapshotSelection decides which snapshots of a CDX index are analysed.
+type snapshotSelection struct {
+	all      bool
+	from, to string // inclusive bounds, padded to 14 digits; empty when open
+}
+
+func newSnapshotSelection(cfg config.Config) snapshotSelection {
+	selection := snapshotSelection{all: cfg.AllSnapshots}
+	if cfg.SnapshotsFrom != "" {
+		selection.from = (cfg.SnapshotsFrom + "00000000000000")[:14]
+	}
+	if cfg.SnapshotsTo != "" {
+		selection.to = (cfg.SnapshotsTo + "99999999999999")[:14]
+	}
+	return selection
+}
+
+func (s snapshotSelection) inRange(timestamp string) bool {
+	return (s.from == "" || timestamp >= s.from) && (s.to == "" || timestamp <= s.to)
+}
+
+// snapshotPicker keeps the successful captures whose MIME type is allowed and whose
+// timestamp is in range: the latest one of every URL, or all of them. Only the picked
+// snapshots are held: one per URL by default, but every selected capture with
+// --snapshots all, so narrow large indexes with --from and --to.
+type snapshotPicker struct {
+	selection snapshotSelection
+	mimeTypes []string
+	picked    []cdxSnapshot
+	index     map[string]int // position of a URL's latest snapshot, or of a URL and timestamp
+}
+
+func newSnapshotPicker(selection snapshotSelection, mimeTypes []string) *snapshotPicker {
+	return &snapshotPicker{selection: selection, mimeTypes: mimeTypes, index: make(map[string]int)}
+}
+
+func (p *snapshotPicker) add(snapshot cdxSnapshot) {
+	if snapshot.Original == "" || len(snapshot.Timestamp) != 14 {
+		return
+	}
+	if snapshot.Status != "" && !strings.HasPrefix(snapshot.Status, "2") {
+		return
+	}
+	if !p.selection.inRange(snapshot.Timestamp) || !mimeAllowed(snapshot.MIMEType, snapshot.Original, p.mimeTypes) {
+		return
+	}
+
+	key := snapshot.Original
+	if p.selection.all {
+		key = snapshot.Timestamp + " " + snapshot.Original
+	}
+	if idx, ok := p.index[key]; ok {
+		if snapshot.Timestamp > p.picked[idx].Timestamp {
+			p.picked[idx] = snapshot
+		}
+		return
+	}
+	p.index[key] = len(p.picked)
+	p.picked = append(p.picked, snapshot)
+}
+
+// snapshots returns the picked snapshots ordered by URL and capture time.
+func (p *snapshotPicker) snapshots() []cdxSnapshot {
+	sort.SliceStable(p.picked, func(i, j int) bool {
+		if p.picked[i].Original != p.picked[j].Original {
+			return p.picked[i].Original < p.picked[j].Original
+		}
+		return p.picked[i].Timestamp < p.picked[j].Timestamp
+	})
+	return p.picked
+}
+
+// parseCDXFile selects snapshots from a CDX index and reads their bodies from source,
+// a WARC archive or a directory of downloaded snapshots. Targets are named after the
+// original URL and carry their capture time.
+func parseCDXFile(path string, cfg config.Config) ([]model.Target, error) {
+	if cfg.SnapshotSource == "" {
+		return nil, errors.New("CDX input requires --snapshot-source with a WARC archive or a snapshot directory")
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	picker := newSnapshotPicker(newSnapshotSelection(cfg), cfg.MIMETypes)
+	if err := readCDX(file, picker.add); err != nil {
+		return nil, fmt.Errorf("invalid CDX file %s: %w", path, err)
+	}
+
+	selected := picker.snapshots()
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("%s: no snapshots match the selection", path)
+	}
+
+	info, err := os.Stat(cfg.SnapshotSource)
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []model.Target
+	if info.IsDir() {
+		targets = snapshotsFromDirectory(cfg.SnapshotSource, selected)
+	} else {
+		targets, err = snapshotsFromWARC(cfg.SnapshotSource, selected)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if missing := len(selected) - len(targets); missing > 0 {
+		fmt.Fprintf(warningOut, "Warning: %d of %d selected snapshots were not found in %s\n", missing, len(selected), cfg.SnapshotSource)
+	}
+	return targets, nil
+}
+
+func snapshotTarget(snapshot cdxSnapshot, body []byte, status int, mimeType string) model.Target {
+	if mimeType == "" {
+		mimeType = snapshot.MIMEType
+	}
+	if status == 0 {
+		status, _ = strconv.Atoi(snapshot.Status)
+	}
+
+	target := model.Target{
+		URL:        snapshot.Original,
+		Content:    string(body),
+		Prefetched: true,
+		Status:     status,
+		MIMEType:   mimeType,
+	}
+	if captured, err := time.Parse(waybackLayout, snapshot.Timestamp); err == nil {
+		target.CapturedAt = captured.UTC().Format(time.RFC3339)
+	}
+	return target
+}
+
+// snapshotsFromDirectory reads snapshots saved as <dir>/<timestamp>/<host>/<path>, the
+// layout of waybackpack, falling back to files named after the snapshot digest.
+func snapshotsFromDirectory(dir string, snapshots []cdxSnapshot) []model.Target {
+	var targets []model.Target
+	for _, snapshot := range snapshots {
+		for _, candidate := range snapshotPaths(dir, snapshot) {
+			info, err := os.Stat(candidate)
+			if err != nil || !info.Mode().IsRegular() || info.Size() > maxArchiveEntrySize {
+				continue
+			}
+			body, err := os.ReadFile(candidate)
+			if err != nil {
+				continue
+			}
+			targets = append(targets, snapshotTarget(snapshot, body, 0, ""))
+			break
+		}
+	}
+	return targets
+}
+
+func snapshotPaths(dir string, snapshot cdxSnapshot) []string {
+	var paths []string
+	if parsed, err := url.Parse(snapshot.Original); err == nil && parsed.Host != "" {
+		rel := parsed.Path
+		if rel == "" || strings.HasSuffix(rel, "/") {
+			rel += "index.html"
+		}
+		rel = strings.TrimPrefix(path.Clean("/"+rel), "/")
+		paths = append(paths, filepath.Join(dir, snapshot.Timestamp, parsed.Host, filepath.FromSlash(rel)))
+	}
+	if snapshot.Digest != "" {
+		paths = append(paths, filepath.Join(dir, snapshot.Digest))
+	}
+	return paths
+}
+
+// snapshotsFromWARC streams a WARC archive once and returns the response and resource
+// records of the snapshots, matched by URL and capture time or by payload digest.
+func snapshotsFromWARC(path string, snapshots []cdxSnapshot) ([]model.Target, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader, err := newWARCReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("invalid WARC file: %w", err)
+	}
+
+	type key struct {
+		uri, timestamp string
+	}
+	byCapture := make(map[key]int, len(snapshots))
+	byDigest := make(map[string][]int)
+	for idx, snapshot := range snapshots {
+		byCapture[key{uri: snapshot.Original, timestamp: snapshot.Timestamp}] = idx
+		if snapshot.Digest != "" {
+			digest := strings.ToUpper(strings.TrimPrefix(snapshot.Digest, "sha1:"))
+			byDigest[digest] = append(byDigest[digest], idx)
+		}
+	}
+
+	found := make([]*model.Target, len(snapshots))
+	for {
+		record, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if record.Type != warcResponse && record.Type != warcResource {
+			continue
+		}
+
+		var matches []int
+		if captured, err := time.Parse(time.RFC3339, record.Date); err == nil {
+			if idx, ok := byCapture[key{uri: record.TargetURI, timestamp: captured.UTC().Format(waybackLayout)}]; ok {
+				matches = append(matches, idx)
+			}
+		}
+		if len(matches) == 0 {
+			digest := strings.ToUpper(strings.TrimPrefix(record.PayloadDigest, "sha1:"))
+			for _, idx := range byDigest[digest] {
+				if snapshots[idx].Original == record.TargetURI {
+					matches = append(matches, idx)
+				}
+			}
+		}
+		if len(matches) == 0 {
+			continue
+		}
+
+		var (
+			body     []byte
+			status   int
+			mimeType string
+		)
+		if record.Type == warcResponse {
+			resp, err := readCapturedResponse(record.Block)
+			if err != nil {
+				continue
+			}
+			body, status, mimeType = resp.Body, resp.Status, resp.MIMEType
+		} else {
+			body, err = io.ReadAll(record.Block)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+			mimeType = record.ContentType
+		}
+
+		for _, idx := range matches {
+			if found[idx] == nil {
+				target := snapshotTarget(snapshots[idx], body, status, mimeType)
+				found[idx] = &target
+			}
+		}
+	}
+
+	var targets []model.Target
+	for _, target := range found {
+		if target != nil {
+			targets = append(targets, *target)
+		}
+	}
+	return targets, nil
+}
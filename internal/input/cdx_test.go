@@ -0,0 +1,175 @@
+package input
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/config"
+)
+
+func TestReadCDXFormats(t *testing.T) {
+	tests := map[string]string{
+		"default": "com,example)/app.js 20190501100000 https://example.com/app.js application/javascript 200 AAAA 512\n",
+		"header": " CDX N b a m s k r M S V g\n" +
+			"com,example)/app.js 20190501100000 https://example.com/app.js application/javascript 200 AAAA - - 512 0 crawl.warc.gz\n",
+		"cdx11":  "com,example)/app.js 20190501100000 https://example.com/app.js application/javascript 200 AAAA - - 512 0 crawl.warc.gz\n",
+		"cdxj":   `com,example)/app.js 20190501100000 {"url": "https://example.com/app.js", "mime": "application/javascript", "status": "200", "digest": "AAAA"}` + "\n",
+		"ndjson": `{"timestamp": "20190501100000", "original": "https://example.com/app.js", "mimetype": "application/javascript", "statuscode": "200", "digest": "AAAA"}` + "\n",
+		"json": `[["urlkey","timestamp","original","mimetype","statuscode","digest","length"],
+ ["com,example)/app.js","20190501100000","https://example.com/app.js","application/javascript","200","AAAA","512"]]`,
+	}
+
+	want := cdxSnapshot{
+		Timestamp: "20190501100000",
+		Original:  "https://example.com/app.js",
+		MIMEType:  "application/javascript",
+		Status:    "200",
+		Digest:    "AAAA",
+	}
+	for name, data := range tests {
+		var got []cdxSnapshot
+		if err := readCDX(strings.NewReader(data), func(s cdxSnapshot) { got = append(got, s) }); err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if len(got) != 1 || got[0] != want {
+			t.Fatalf("%s: unexpected snapshots %+v", name, got)
+		}
+	}
+}
+
+func TestSnapshotPicker(t *testing.T) {
+	snapshots := []cdxSnapshot{
+		{Timestamp: "20180101000000", Original: "https://example.com/app.js", MIMEType: "application/javascript", Status: "200"},
+		{Timestamp: "20200101000000", Original: "https://example.com/app.js", MIMEType: "application/javascript", Status: "200"},
+		{Timestamp: "20190601000000", Original: "https://example.com/app.js", MIMEType: "application/javascript", Status: "200"},
+		{Timestamp: "20190701000000", Original: "https://example.com/app.js", MIMEType: "application/javascript", Status: "404"},
+		{Timestamp: "20190801000000", Original: "https://example.com/app.js", MIMEType: "warc/revisit"},
+		{Timestamp: "20190101000000", Original: "https://example.com/logo.png", MIMEType: "image/png", Status: "200"},
+		{Timestamp: "2019", Original: "https://example.com/bad.js", Status: "200"},
+	}
+	timestamps := func(picked []cdxSnapshot) string {
+		var out []string
+		for _, s := range picked {
+			out = append(out, s.Timestamp)
+		}
+		return strings.Join(out, ",")
+	}
+	pick := func(cfg config.Config, mimeTypes []string) []cdxSnapshot {
+		picker := newSnapshotPicker(newSnapshotSelection(cfg), mimeTypes)
+		for _, s := range snapshots {
+			picker.add(s)
+		}
+		return picker.snapshots()
+	}
+
+	if got := timestamps(pick(config.Config{}, []string{"javascript"})); got != "20200101000000" {
+		t.Fatalf("expected the latest snapshot, got %s", got)
+	}
+	if got := timestamps(pick(config.Config{SnapshotsTo: "2019"}, []string{"javascript"})); got != "20190601000000" {
+		t.Fatalf("expected the latest snapshot of 2019, got %s", got)
+	}
+	if got := timestamps(pick(config.Config{AllSnapshots: true, SnapshotsFrom: "2019"}, []string{"javascript"})); got != "20190601000000,20200101000000" {
+		t.Fatalf("expected every successful snapshot since 2019, got %s", got)
+	}
+	if got := len(pick(config.Config{AllSnapshots: true}, nil)); got != 5 {
+		t.Fatalf("expected 5 snapshots without MIME filtering, got %d", got)
+	}
+}
+
+func TestResolveTargetsCDXFromWARC(t *testing.T) {
+	dir := t.TempDir()
+	capture := func(uri, date, digest, body string) string {
+		block := "HTTP/1.1 200 OK\r\nContent-Type: application/javascript\r\n\r\n" + body
+		return warcRecordBytes("response", uri, date, digest, "application/http; msgtype=response", block)
+	}
+
+	var archive bytes.Buffer
+	archive.WriteString(capture("https://example.com/app.js", "2019-05-01T10:00:00Z", "sha1:OLD", `fetch("/api/v1/legacy")`))
+	archive.WriteString(capture("https://example.com/app.js", "2021-03-02T08:30:00Z", "sha1:NEW", `fetch("/api/v2/users")`))
+	// Re-crawled later under a different capture time; matched through its digest.
+	archive.WriteString(capture("https://example.com/vendor.js", "2020-01-01T00:00:05Z", "sha1:VENDOR", `fetch("/api/vendor")`))
+	warcPath := filepath.Join(dir, "site.warc")
+	writeFile(t, warcPath, archive.Bytes())
+
+	index := strings.Join([]string{
+		"com,example)/app.js 20190501100000 https://example.com/app.js application/javascript 200 OLD 100",
+		"com,example)/app.js 20210302083000 https://example.com/app.js application/javascript 200 NEW 100",
+		"com,example)/vendor.js 20200101000000 https://example.com/vendor.js application/javascript 200 VENDOR 100",
+		"com,example)/gone.js 20200101000000 https://example.com/gone.js application/javascript 200 GONE 100",
+	}, "\n")
+	cdxPath := filepath.Join(dir, "site.cdx")
+	writeFile(t, cdxPath, []byte(index))
+
+	var warnings bytes.Buffer
+	oldOut := warningOut
+	warningOut = &warnings
+	t.Cleanup(func() {
+		warningOut = oldOut
+	})
+
+	targets, err := ResolveTargets(config.Config{Input: cdxPath, AllSnapshots: true, SnapshotSource: warcPath})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(targets) != 3 {
+		t.Fatalf("expected 3 targets, got %+v", targets)
+	}
+
+	old, current, vendor := targets[0], targets[1], targets[2]
+	if old.URL != "https://example.com/app.js" || old.CapturedAt != "2019-05-01T10:00:00Z" || !strings.Contains(old.Content, "/api/v1/legacy") {
+		t.Fatalf("unexpected old snapshot %+v", old)
+	}
+	if current.CapturedAt != "2021-03-02T08:30:00Z" || !strings.Contains(current.Content, "/api/v2/users") || !current.Prefetched {
+		t.Fatalf("unexpected current snapshot %+v", current)
+	}
+	if vendor.CapturedAt != "2020-01-01T00:00:00Z" || !strings.Contains(vendor.Content, "/api/vendor") || vendor.Status != 200 {
+		t.Fatalf("unexpected digest-matched snapshot %+v", vendor)
+	}
+	if !strings.Contains(warnings.String(), "1 of 4 selected snapshots were not found") {
+		t.Fatalf("expected a warning about the missing snapshot, got %q", warnings.String())
+	}
+}
+
+func TestResolveTargetsCDXFromDirectory(t *testing.T) {
+	dir := t.TempDir()
+	snapshots := filepath.Join(dir, "snapshots")
+	for path, content := range map[string]string{
+		"20190501100000/example.com/static/app.js": `fetch("/api/old")`,
+		"20210302083000/example.com/static/app.js": `fetch("/api/new")`,
+		"20190501100000/example.com/index.html":    `<a href="/admin">admin</a>`,
+	} {
+		full := filepath.Join(snapshots, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		writeFile(t, full, []byte(content))
+	}
+
+	index := `[["urlkey","timestamp","original","mimetype","statuscode","digest","length"],
+["com,example)/static/app.js","20190501100000","https://example.com/static/app.js","application/javascript","200","A","1"],
+["com,example)/static/app.js","20210302083000","https://example.com/static/app.js","application/javascript","200","B","1"],
+["com,example)/","20190501100000","https://example.com/","text/html","200","C","1"]]`
+	cdxPath := filepath.Join(dir, "wayback.json")
+	writeFile(t, cdxPath, []byte(index))
+
+	targets, err := ResolveTargets(config.Config{Input: cdxPath, CDX: true, SnapshotSource: snapshots})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(targets) != 2 {
+		t.Fatalf("expected the latest snapshot of each URL, got %+v", targets)
+	}
+	if targets[0].URL != "https://example.com/" || !strings.Contains(targets[0].Content, "/admin") {
+		t.Fatalf("unexpected index snapshot %+v", targets[0])
+	}
+	if targets[1].CapturedAt != "2021-03-02T08:30:00Z" || targets[1].Content != `fetch("/api/new")` {
+		t.Fatalf("unexpected script snapshot %+v", targets[1])
+	}
+
+	if _, err := ResolveTargets(config.Config{Input: cdxPath, CDX: true}); err == nil {
+		t.Fatalf("expected CDX input without --snapshot-source to fail")
+	}
+}
@@ -0,0 +1,101 @@
+package input
+
+import (
+	"hash/maphash"
+	"math/bits"
+
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/model"
+)
+
+const (
+	// exactDedupLimit is the number of targets remembered exactly before deduplication
+	// switches to a Bloom filter.
+	exactDedupLimit = 100_000
+	// bloomFilterBits sizes the Bloom filter at 16 MiB, which keeps false positives
+	// below 0.2% for ten million targets.
+	bloomFilterBits   = 1 << 27
+	bloomFilterHashes = 7
+)
+
+// dedupSeed keys the target digests of a run.
+var dedupSeed = maphash.MakeSeed()
+
+// targetDedup remembers the targets already read in bounded memory, keyed on 64-bit
+// digests rather than the targets themselves, so large captured bodies cost no more
+// than a URL. The first exactDedupLimit digests are kept in a set; past that a Bloom
+// filter takes over, which may rarely drop a target that was not seen before.
+type targetDedup struct {
+	exact map[uint64]struct{}
+	bloom *bloomFilter
+}
+
+func newTargetDedup() *targetDedup {
+	return &targetDedup{exact: make(map[uint64]struct{})}
+}
+
+// add records the target identified by parts and reports whether it had not been seen
+// before.
+func (d *targetDedup) add(parts ...string) bool {
+	var h maphash.Hash
+	h.SetSeed(dedupSeed)
+	for _, part := range parts {
+		h.WriteString(part)
+		// Separate the parts so that ("ab", "c") and ("a", "bc") differ.
+		h.WriteByte(0)
+	}
+	digest := h.Sum64()
+
+	if d.bloom != nil {
+		return d.bloom.add(digest)
+	}
+
+	if _, ok := d.exact[digest]; ok {
+		return false
+	}
+	d.exact[digest] = struct{}{}
+
+	if len(d.exact) > exactDedupLimit {
+		d.bloom = newBloomFilter(bloomFilterBits, bloomFilterHashes)
+		for seen := range d.exact {
+			d.bloom.add(seen)
+		}
+		d.exact = nil
+	}
+	return true
+}
+
+// addCapture records a captured response and reports whether it is new. Capture
+// importers yield the same request with the same body once, whatever the format.
+func (d *targetDedup) addCapture(target model.Target) bool {
+	return d.add(target.Method, target.URL, target.Content)
+}
+
+// bloomFilter is a fixed-size set membership filter over 64-bit digests.
+type bloomFilter struct {
+	bits   []uint64
+	size   uint64
+	hashes int
+}
+
+func newBloomFilter(size uint64, hashes int) *bloomFilter {
+	return &bloomFilter{bits: make([]uint64, (size+63)/64), size: size, hashes: hashes}
+}
+
+// add records digest and reports whether it was possibly absent before. Bit positions
+// are derived by double hashing from the digest and its halves swapped, with the step
+// forced odd so that it never degenerates to zero.
+func (b *bloomFilter) add(digest uint64) bool {
+	h1 := digest
+	h2 := bits.RotateLeft64(digest, 32) | 1
+
+	added := false
+	for i := 0; i < b.hashes; i++ {
+		bit := (h1 + uint64(i)*h2) % b.size
+		word, mask := bit/64, uint64(1)<<(bit%64)
+		if b.bits[word]&mask == 0 {
+			b.bits[word] |= mask
+			added = true
+		}
+	}
+	return added
+}
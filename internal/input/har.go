@@ -0,0 +1,118 @@
+package input
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/model"
+)
+
+type harDocument struct {
+	Log struct {
+		Entries []harEntry `json:"entries"`
+	} `json:"log"`
+}
+
+type harEntry struct {
+	Request struct {
+		Method string `json:"method"`
+		URL    string `json:"url"`
+	} `json:"request"`
+	Response struct {
+		Status  int `json:"status"`
+		Content struct {
+			MimeType string `json:"mimeType"`
+			Text     string `json:"text"`
+			Encoding string `json:"encoding"`
+		} `json:"content"`
+	} `json:"response"`
+}
+
+// isHARFile reports whether path names a HAR capture.
+func isHARFile(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".har")
+}
+
+// parseHARFile imports the response bodies of a HAR 1.2 capture. Entries without a
+// body are skipped, and those whose body cannot be decoded are skipped with a warning.
+func parseHARFile(path string, mimeTypes []string) ([]model.Target, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc harDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid HAR file: %w", err)
+	}
+
+	seen := newTargetDedup()
+
+	var targets []model.Target
+	for idx, entry := range doc.Log.Entries {
+		content := entry.Response.Content
+		if content.Text == "" || entry.Request.URL == "" {
+			continue
+		}
+		if !mimeAllowed(content.MimeType, entry.Request.URL, mimeTypes) {
+			continue
+		}
+
+		body := content.Text
+		if strings.EqualFold(content.Encoding, "base64") {
+			decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(body))
+			if err != nil {
+				fmt.Fprintf(warningOut, "Warning: skipping HAR entry %d (%s): invalid base64 body: %v\n", idx+1, entry.Request.URL, err)
+				continue
+			}
+			body = string(decoded)
+		}
+
+		target := model.Target{
+			URL:        entry.Request.URL,
+			Content:    body,
+			Prefetched: true,
+			Method:     entry.Request.Method,
+			Status:     entry.Response.Status,
+			MIMEType:   content.MimeType,
+		}
+		if !seen.addCapture(target) {
+			continue
+		}
+		targets = append(targets, target)
+	}
+
+	return targets, nil
+}
+
+// mimeAllowed reports whether a captured response should be analysed. Responses
+// without a MIME type are judged by the extension of their URL.
+func mimeAllowed(mimeType, rawURL string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+
+	mimeType = strings.ToLower(strings.TrimSpace(mimeType))
+	if mimeType == "" {
+		if parsed, err := url.Parse(rawURL); err == nil {
+			mimeType = strings.ToLower(mime.TypeByExtension(path.Ext(parsed.Path)))
+		}
+		if mimeType == "" {
+			return false
+		}
+	}
+
+	for _, candidate := range allowed {
+		if strings.Contains(mimeType, candidate) {
+			return true
+		}
+	}
+	return false
+}
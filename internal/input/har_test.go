@@ -0,0 +1,111 @@
+package input
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/config"
+)
+
+func writeHAR(t *testing.T, name string) string {
+	t.Helper()
+
+	encoded := base64.StdEncoding.EncodeToString([]byte(`fetch("/api/encoded")`))
+	data := `{"log":{"version":"1.2","entries":[
+		{"request":{"method":"GET","url":"https://example.com/app.js"},
+		 "response":{"status":200,"content":{"mimeType":"application/javascript","text":"fetch('/api/plain')"}}},
+		{"request":{"method":"POST","url":"https://example.com/bundle"},
+		 "response":{"status":201,"content":{"mimeType":"text/javascript; charset=utf-8","text":"` + encoded + `","encoding":"base64"}}},
+		{"request":{"method":"GET","url":"https://example.com/logo.png"},
+		 "response":{"status":200,"content":{"mimeType":"image/png","text":"iVBORw0KGgo=","encoding":"base64"}}},
+		{"request":{"method":"GET","url":"https://example.com/legacy.js"},
+		 "response":{"status":304,"content":{"mimeType":"","text":"var a = '/api/legacy';"}}},
+		{"request":{"method":"GET","url":"https://example.com/empty"},
+		 "response":{"status":204,"content":{"mimeType":"text/html"}}},
+		{"request":{"method":"GET","url":"https://example.com/app.js"},
+		 "response":{"status":200,"content":{"mimeType":"application/javascript","text":"fetch('/api/plain')"}}}
+	]}}`
+
+	file := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(file, []byte(data), 0o644); err != nil {
+		t.Fatalf("write har file: %v", err)
+	}
+	return file
+}
+
+func TestResolveTargetsHAR(t *testing.T) {
+	file := writeHAR(t, "capture.har")
+
+	targets, err := ResolveTargets(config.Config{Input: file, MIMETypes: []string{"javascript"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(targets) != 3 {
+		t.Fatalf("expected 3 targets, got %+v", targets)
+	}
+
+	first := targets[0]
+	if !first.Prefetched || first.Method != "GET" || first.Status != 200 || first.Content != "fetch('/api/plain')" {
+		t.Fatalf("unexpected first target: %+v", first)
+	}
+
+	second := targets[1]
+	if second.Method != "POST" || second.Status != 201 || second.Content != `fetch("/api/encoded")` {
+		t.Fatalf("expected base64 body to be decoded: %+v", second)
+	}
+
+	if targets[2].URL != "https://example.com/legacy.js" {
+		t.Fatalf("expected a missing MIME type to fall back to the URL extension, got %+v", targets[2])
+	}
+}
+
+func TestResolveTargetsHARFlag(t *testing.T) {
+	file := writeHAR(t, "capture.json")
+
+	targets, err := ResolveTargets(config.Config{Input: file, HAR: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(targets) != 4 {
+		t.Fatalf("expected every response with a body without a MIME filter, got %d", len(targets))
+	}
+
+	if _, err := ResolveTargets(config.Config{Input: "https://example.com", HAR: true}); err == nil {
+		t.Fatalf("expected har mode to require a file input")
+	}
+}
+
+func TestResolveTargetsHARSkipsCorruptBodies(t *testing.T) {
+	data := `{"log":{"entries":[
+		{"request":{"method":"GET","url":"https://example.com/broken.js"},
+		 "response":{"status":200,"content":{"mimeType":"application/javascript","text":"%%%not-base64%%%","encoding":"base64"}}},
+		{"request":{"method":"GET","url":"https://example.com/app.js"},
+		 "response":{"status":200,"content":{"mimeType":"application/javascript","text":"fetch('/api/users')"}}}
+	]}}`
+	file := filepath.Join(t.TempDir(), "capture.har")
+	if err := os.WriteFile(file, []byte(data), 0o644); err != nil {
+		t.Fatalf("write har file: %v", err)
+	}
+
+	var warnings bytes.Buffer
+	oldOut := warningOut
+	warningOut = &warnings
+	t.Cleanup(func() {
+		warningOut = oldOut
+	})
+
+	targets, err := ResolveTargets(config.Config{Input: file})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(targets) != 1 || targets[0].URL != "https://example.com/app.js" {
+		t.Fatalf("expected the corrupt entry to be skipped, got %+v", targets)
+	}
+	if !strings.Contains(warnings.String(), "broken.js") {
+		t.Fatalf("expected a warning naming the skipped entry, got %q", warnings.String())
+	}
+}
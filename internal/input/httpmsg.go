@@ -0,0 +1,84 @@
+package input
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// capturedResponse is an HTTP response recovered from a traffic capture.
+type capturedResponse struct {
+	Status   int
+	MIMEType string
+	Body     []byte
+}
+
+// readCapturedResponse parses a raw HTTP response, as stored by proxies and crawlers,
+// and returns its decoded body. Chunked transfer encoding and gzip, deflate or brotli
+// content encoding are undone.
+func readCapturedResponse(r io.Reader) (capturedResponse, error) {
+	buffered := bufio.NewReader(r)
+	// net/http only parses "HTTP/x.y" versions, while HTTP/2 and HTTP/3 captures are
+	// stored with status lines such as "HTTP/2 200".
+	if prefix, err := buffered.Peek(7); err == nil && (string(prefix) == "HTTP/2 " || string(prefix) == "HTTP/3 ") {
+		version := string(prefix[:6]) + ".0 "
+		_, _ = buffered.Discard(7)
+		buffered = bufio.NewReader(io.MultiReader(strings.NewReader(version), buffered))
+	}
+
+	resp, err := http.ReadResponse(buffered, nil)
+	if err != nil {
+		return capturedResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil && len(body) == 0 {
+		return capturedResponse{}, err
+	}
+
+	return capturedResponse{
+		Status:   resp.StatusCode,
+		MIMEType: resp.Header.Get("Content-Type"),
+		Body:     decodeContent(resp.Header.Get("Content-Encoding"), body),
+	}, nil
+}
+
+// decodeContent undoes the content encoding of a captured body. Bodies that cannot be
+// decoded are returned unchanged, since captures often store them already decoded.
+func decodeContent(encoding string, body []byte) []byte {
+	encoding = strings.ToLower(strings.TrimSpace(encoding))
+	if idx := strings.IndexByte(encoding, ','); idx != -1 {
+		encoding = strings.TrimSpace(encoding[:idx])
+	}
+
+	var (
+		reader io.Reader
+		err    error
+	)
+	switch encoding {
+	case "gzip", "x-gzip":
+		reader, err = gzip.NewReader(bytes.NewReader(body))
+	case "deflate":
+		reader, err = zlib.NewReader(bytes.NewReader(body))
+	case "br":
+		reader = brotli.NewReader(bytes.NewReader(body))
+	default:
+		return body
+	}
+	if err != nil {
+		return body
+	}
+
+	decoded, err := io.ReadAll(reader)
+	if err != nil {
+		return body
+	}
+	return decoded
+}
@@ -0,0 +1,150 @@
+package input
+
+import (
+	"bufio"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// ignoreFileNames are read in every directory of a directory input, like .gitignore
+// files are by git.
+var ignoreFileNames = []string{".gitignore", ".golinkfinderignore"}
+
+// globPattern is a compiled gitignore-style glob. Patterns without a slash match the
+// name of a file or directory at any depth; others match the path relative to the
+// directory that declared them. "**" spans directories.
+type globPattern struct {
+	re       *regexp.Regexp
+	negate   bool
+	dirOnly  bool
+	anchored bool
+}
+
+func compileGlob(pattern string) (globPattern, bool) {
+	var g globPattern
+
+	if strings.HasPrefix(pattern, "!") {
+		g.negate = true
+		pattern = pattern[1:]
+	}
+	if strings.HasSuffix(pattern, "/") {
+		g.dirOnly = true
+		pattern = strings.TrimRight(pattern, "/")
+	}
+	if pattern == "" {
+		return globPattern{}, false
+	}
+	if strings.Contains(pattern, "/") {
+		g.anchored = true
+		pattern = strings.TrimPrefix(pattern, "/")
+	}
+
+	var expr strings.Builder
+	expr.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == '*' && strings.HasPrefix(pattern[i:], "**/"):
+			expr.WriteString("(?:.*/)?")
+			i += 2
+		case c == '*' && strings.HasPrefix(pattern[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case c == '*':
+			expr.WriteString("[^/]*")
+		case c == '?':
+			expr.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end == -1 {
+				expr.WriteString(`\[`)
+				continue
+			}
+			class := pattern[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			expr.WriteString("[" + class + "]")
+			i += end + 1
+		case c == '\\' && i+1 < len(pattern):
+			i++
+			expr.WriteString(regexp.QuoteMeta(string(pattern[i])))
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	expr.WriteString("$")
+
+	re, err := regexp.Compile(expr.String())
+	if err != nil {
+		return globPattern{}, false
+	}
+	g.re = re
+	return g, true
+}
+
+// match reports whether rel, a slash separated path relative to the pattern's base
+// directory, matches the pattern.
+func (g globPattern) match(rel string, isDir bool) bool {
+	if g.dirOnly && !isDir {
+		return false
+	}
+	if g.anchored {
+		return g.re.MatchString(rel)
+	}
+	name := rel
+	if idx := strings.LastIndexByte(rel, '/'); idx != -1 {
+		name = rel[idx+1:]
+	}
+	return g.re.MatchString(name)
+}
+
+// ignoreRules holds the patterns of an ignore file together with the directory it
+// was found in, relative to the walk root.
+type ignoreRules struct {
+	base     string
+	patterns []globPattern
+}
+
+func loadIgnoreFile(path, base string) (ignoreRules, bool) {
+	file, err := os.Open(path)
+	if err != nil {
+		return ignoreRules{}, false
+	}
+	defer file.Close()
+
+	rules := ignoreRules{base: base}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if pattern, ok := compileGlob(line); ok {
+			rules.patterns = append(rules.patterns, pattern)
+		}
+	}
+	return rules, len(rules.patterns) > 0
+}
+
+// ignored applies the ignore files in effect for rel, from the root down, so deeper
+// files and later lines override earlier ones, as in git.
+func ignored(stack []ignoreRules, rel string, isDir bool) bool {
+	result := false
+	for _, rules := range stack {
+		local := rel
+		if rules.base != "" {
+			if !strings.HasPrefix(rel, rules.base+"/") {
+				continue
+			}
+			local = strings.TrimPrefix(rel, rules.base+"/")
+		}
+		for _, pattern := range rules.patterns {
+			if pattern.match(local, isDir) {
+				result = !pattern.negate
+			}
+		}
+	}
+	return result
+}
@@ -1,9 +1,6 @@
 package input
 
 import (
-	"bytes"
-	"encoding/base64"
-	"encoding/xml"
 	"errors"
 	"io"
 	"net/url"
@@ -18,25 +15,35 @@ import (
 
 // ResolveTargets returns the list of targets to evaluate based on the provided configuration.
 func ResolveTargets(cfg config.Config) ([]model.Target, error) {
+	stream, err := Open(cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	return stream.all()
+}
+
+// Open prepares the targets of the configured input for reading. Target lists, given
+// as a file or on stdin, are read line by line as the stream is consumed; every other
+// input is resolved before Open returns.
+func Open(cfg config.Config) (*Stream, error) {
 	input := cfg.Input
 
 	if strings.HasPrefix(input, "view-source:") {
 		input = input[12:]
 	}
 
+	if cfg.StdinContent {
+		return newSliceStream(readStdinContent(os.Stdin, cfg.Base))
+	}
+
 	if input == "-" {
 		baseDir, err := os.Getwd()
 		if err != nil {
 			return nil, err
 		}
-		targets, err := parseTargetsFromReader(os.Stdin, baseDir)
-		if err != nil {
-			return nil, err
-		}
-		if len(targets) == 0 {
-			return nil, errors.New("no targets provided via stdin")
-		}
-		return targets, nil
+		return newListStream(os.Stdin, nil, baseDir, errors.New("no targets provided via stdin")), nil
 	}
 
 	if strings.HasPrefix(input, "http://") || strings.HasPrefix(input, "https://") ||
@@ -45,70 +52,116 @@ func ResolveTargets(cfg config.Config) ([]model.Target, error) {
 		if cfg.Burp {
 			return nil, errors.New("burp mode requires a file input")
 		}
-		return []model.Target{{URL: input}}, nil
+		if cfg.HAR {
+			return nil, errors.New("har mode requires a file input")
+		}
+		if cfg.WARC {
+			return nil, errors.New("warc mode requires a file input")
+		}
+		if cfg.Mitmproxy {
+			return nil, errors.New("mitmproxy mode requires a file input")
+		}
+		if cfg.ZAP {
+			return nil, errors.New("zap mode requires a file input")
+		}
+		if cfg.CDX {
+			return nil, errors.New("cdx mode requires a file input")
+		}
+		return newSliceStream([]model.Target{{URL: input}}, nil)
 	}
 
 	if cfg.Burp {
-		return parseBurpFile(input)
+		return openBurpFile(input, cfg.MIMETypes)
+	}
+
+	if cfg.HAR {
+		return newSliceStream(parseHARFile(input, cfg.MIMETypes))
+	}
+
+	if cfg.WARC {
+		return openWARCFile(input, cfg.MIMETypes)
+	}
+
+	if cfg.Mitmproxy {
+		return newSliceStream(parseMitmproxyFile(input, cfg.MIMETypes))
+	}
+
+	if cfg.ZAP {
+		return newSliceStream(parseZAPFile(input, cfg.MIMETypes))
+	}
+
+	if cfg.CDX {
+		return newSliceStream(parseCDXFile(input, cfg))
 	}
 
 	if strings.Contains(input, "*") {
-		return resolveGlob(input)
+		return newSliceStream(resolveGlob(input))
 	}
 
 	if info, err := os.Stat(input); err == nil {
 		if info.IsDir() {
-			return nil, errors.New("directories require a wildcard (e.g. dir/*.js)")
+			opts, err := newWalkOptions(cfg)
+			if err != nil {
+				return nil, err
+			}
+			return newSliceStream(walkDirectory(input, opts))
 		}
 		abs, err := filepath.Abs(input)
 		if err != nil {
 			return nil, err
 		}
 
-		if targets, ok, err := parseTargetsFromFile(abs); err != nil {
+		if isHARFile(abs) {
+			return newSliceStream(parseHARFile(abs, cfg.MIMETypes))
+		}
+		if isWARCFile(abs) {
+			return openWARCFile(abs, cfg.MIMETypes)
+		}
+		if isMitmproxyFile(abs) {
+			return newSliceStream(parseMitmproxyFile(abs, cfg.MIMETypes))
+		}
+		if isZAPFile(abs) {
+			return newSliceStream(parseZAPFile(abs, cfg.MIMETypes))
+		}
+		if isCDXFile(abs) {
+			return newSliceStream(parseCDXFile(abs, cfg))
+		}
+		if isArchiveFile(abs) {
+			// Entries are named after the archive as it was given on the command line.
+			return newSliceStream(parseArchiveFile(input))
+		}
+
+		if stream, ok, err := openListFile(abs); err != nil {
 			return nil, err
 		} else if ok {
-			return targets, nil
+			return stream, nil
 		}
 
-		return []model.Target{{URL: "file://" + abs}}, nil
+		return newSliceStream([]model.Target{{URL: "file://" + abs}}, nil)
 	}
 
 	return nil, errors.New("file could not be found (maybe you forgot to add http/https)")
 }
 
-type burpItem struct {
-	URL      string `xml:"url"`
-	Response struct {
-		Text string `xml:",chardata"`
-	} `xml:"response"`
-}
+// stdinResource names the --stdin-content resource when no base URL is given.
+const stdinResource = "stdin"
 
-type burpDocument struct {
-	Items []burpItem `xml:"item"`
-}
-
-func parseBurpFile(path string) ([]model.Target, error) {
-	data, err := os.ReadFile(path)
+// readStdinContent turns the content piped on stdin into a single prefetched target
+// named after base, so its relative links resolve against it.
+func readStdinContent(r io.Reader, base string) ([]model.Target, error) {
+	data, err := io.ReadAll(r)
 	if err != nil {
 		return nil, err
 	}
-
-	var doc burpDocument
-	if err := xml.Unmarshal(data, &doc); err != nil {
-		return nil, err
+	if strings.TrimSpace(string(data)) == "" {
+		return nil, errors.New("no content provided via stdin")
 	}
 
-	var targets []model.Target
-	for _, item := range doc.Items {
-		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(item.Response.Text))
-		if err != nil {
-			return nil, err
-		}
-		targets = append(targets, model.Target{URL: item.URL, Content: string(decoded), Prefetched: true})
+	name := base
+	if name == "" {
+		name = stdinResource
 	}
-
-	return targets, nil
+	return []model.Target{{URL: name, Content: string(data), Prefetched: true}}, nil
 }
 
 func resolveGlob(pattern string) ([]model.Target, error) {
@@ -138,71 +191,6 @@ func resolveGlob(pattern string) ([]model.Target, error) {
 	return targets, nil
 }
 
-func parseTargetsFromFile(path string) ([]model.Target, bool, error) {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil, false, err
-	}
-
-	targets, err := parseTargetsFromReader(bytes.NewReader(data), filepath.Dir(path))
-	if err != nil {
-		return nil, false, err
-	}
-
-	if len(targets) == 0 {
-		return nil, false, nil
-	}
-
-	return targets, true, nil
-}
-
-func parseTargetsFromReader(r io.Reader, baseDir string) ([]model.Target, error) {
-	data, err := io.ReadAll(r)
-	if err != nil {
-		return nil, err
-	}
-
-	lines := strings.Split(string(data), "\n")
-	var targets []model.Target
-
-	for _, line := range lines {
-		trimmed := strings.TrimSpace(line)
-		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
-			continue
-		}
-
-		if strings.HasPrefix(trimmed, "view-source:") {
-			trimmed = strings.TrimSpace(trimmed[12:])
-			if trimmed == "" {
-				continue
-			}
-		}
-
-		if isURLInput(trimmed) {
-			targets = append(targets, model.Target{URL: trimmed})
-			continue
-		}
-
-		candidate := trimmed
-		if !filepath.IsAbs(candidate) {
-			candidate = filepath.Join(baseDir, candidate)
-		}
-
-		info, err := os.Stat(candidate)
-		if err != nil || info.IsDir() {
-			continue
-		}
-
-		abs, err := filepath.Abs(candidate)
-		if err != nil {
-			return nil, err
-		}
-		targets = append(targets, model.Target{URL: "file://" + abs})
-	}
-
-	return targets, nil
-}
-
 func isURLInput(value string) bool {
 	lowered := strings.ToLower(value)
 	prefixes := []string{"http://", "https://", "file://", "ftp://", "ftps://"}
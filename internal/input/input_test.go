@@ -1,7 +1,6 @@
 package input
 
 import (
-	"encoding/base64"
 	"errors"
 	"os"
 	"path/filepath"
@@ -116,32 +115,6 @@ func TestResolveTargetsGlob(t *testing.T) {
 	}
 }
 
-func TestResolveTargetsBurp(t *testing.T) {
-	dir := t.TempDir()
-	file := filepath.Join(dir, "burp.xml")
-
-	body := base64.StdEncoding.EncodeToString([]byte("console.log('test');"))
-	data := `<items><item><url>https://example.com/app.js</url><response>` + body + `</response></item></items>`
-	if err := os.WriteFile(file, []byte(data), 0o644); err != nil {
-		t.Fatalf("write burp file: %v", err)
-	}
-
-	cfg := config.Config{Input: file, Burp: true}
-	targets, err := ResolveTargets(cfg)
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
-	if len(targets) != 1 {
-		t.Fatalf("expected 1 target, got %d", len(targets))
-	}
-	if !targets[0].Prefetched {
-		t.Fatalf("burp targets should be prefetched")
-	}
-	if targets[0].Content == "" {
-		t.Fatalf("expected prefetched content to be populated")
-	}
-}
-
 func TestResolveFilePath(t *testing.T) {
 	dir := t.TempDir()
 	file := filepath.Join(dir, "folder with space", "script.js")
@@ -238,3 +211,29 @@ func TestParseTargetsFromReaderError(t *testing.T) {
 		t.Fatalf("expected propagated error, got %v", err)
 	}
 }
+
+func TestReadStdinContent(t *testing.T) {
+	targets, err := readStdinContent(strings.NewReader("fetch('/api/users')"), "https://example.com/static/app.js")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(targets) != 1 {
+		t.Fatalf("expected 1 target, got %d", len(targets))
+	}
+	target := targets[0]
+	if target.URL != "https://example.com/static/app.js" || !target.Prefetched || target.Content != "fetch('/api/users')" {
+		t.Fatalf("unexpected target %+v", target)
+	}
+
+	targets, err = readStdinContent(strings.NewReader("<script src=app.js></script>"), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if targets[0].URL != stdinResource {
+		t.Fatalf("expected stdin resource name, got %q", targets[0].URL)
+	}
+
+	if _, err := readStdinContent(strings.NewReader(" \n"), ""); err == nil {
+		t.Fatalf("expected empty stdin to fail")
+	}
+}
@@ -0,0 +1,138 @@
+package input
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/model"
+)
+
+// isMitmproxyFile reports whether path names a mitmproxy flow dump.
+func isMitmproxyFile(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".flow")
+}
+
+// parseMitmproxyFile reads the flows of a dump written by "mitmdump -w" or the UI's
+// save command. Only HTTP flows with a response are imported.
+func parseMitmproxyFile(path string, mimeTypes []string) ([]model.Target, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	seen := newTargetDedup()
+
+	var targets []model.Target
+	reader := bufio.NewReader(file)
+	for index := 1; ; index++ {
+		value, err := readTNetString(reader)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			if len(targets) == 0 {
+				return nil, fmt.Errorf("invalid mitmproxy flow file: %w", err)
+			}
+			fmt.Fprintf(warningOut, "Warning: %s: stopped reading after flow %d: %v\n", path, index-1, err)
+			break
+		}
+
+		flow, ok := value.(map[string]any)
+		if !ok {
+			fmt.Fprintf(warningOut, "Warning: skipping mitmproxy flow %d: not a dictionary\n", index)
+			continue
+		}
+		target, ok := mitmproxyTarget(flow)
+		if !ok || !mimeAllowed(target.MIMEType, target.URL, mimeTypes) {
+			continue
+		}
+		if strings.TrimSpace(target.Content) == "" {
+			continue
+		}
+
+		if !seen.addCapture(target) {
+			continue
+		}
+		targets = append(targets, target)
+	}
+
+	return targets, nil
+}
+
+// mitmproxyTarget converts an HTTP flow with a response into a target. Response
+// bodies are stored with their content encoding, which is undone.
+func mitmproxyTarget(flow map[string]any) (model.Target, bool) {
+	if kind := tnetText(flow["type"]); kind != "" && kind != "http" {
+		return model.Target{}, false
+	}
+	request, _ := flow["request"].(map[string]any)
+	response, _ := flow["response"].(map[string]any)
+	if request == nil || response == nil {
+		return model.Target{}, false
+	}
+
+	url := mitmproxyURL(request)
+	if url == "" {
+		return model.Target{}, false
+	}
+
+	headers := mitmproxyHeaders(response["headers"])
+	content, _ := response["content"].([]byte)
+	status, _ := response["status_code"].(int64)
+
+	return model.Target{
+		URL:        url,
+		Content:    string(decodeContent(headers["content-encoding"], content)),
+		Prefetched: true,
+		Method:     tnetText(request["method"]),
+		Status:     int(status),
+		MIMEType:   headers["content-type"],
+	}, true
+}
+
+// mitmproxyURL rebuilds the request URL, leaving out the scheme's default port.
+func mitmproxyURL(request map[string]any) string {
+	scheme := tnetText(request["scheme"])
+	host := tnetText(request["host"])
+	port, _ := request["port"].(int64)
+	switch {
+	case host == "":
+		// Older dumps and HTTP/2 flows may only record the authority.
+		host = tnetText(request["authority"])
+	case port != 0 && !(scheme == "http" && port == 80) && !(scheme == "https" && port == 443):
+		host = net.JoinHostPort(host, strconv.FormatInt(port, 10))
+	case strings.Contains(host, ":"):
+		host = "[" + host + "]"
+	}
+	if scheme == "" || host == "" {
+		return ""
+	}
+
+	return scheme + "://" + host + tnetText(request["path"])
+}
+
+// mitmproxyHeaders returns the first value of every header, keyed by lower-case name.
+// Headers are stored as a list of [name, value] pairs.
+func mitmproxyHeaders(value any) map[string]string {
+	headers := make(map[string]string)
+	list, _ := value.([]any)
+	for _, entry := range list {
+		pair, ok := entry.([]any)
+		if !ok || len(pair) != 2 {
+			continue
+		}
+		name := strings.ToLower(tnetText(pair[0]))
+		if _, ok := headers[name]; !ok {
+			headers[name] = tnetText(pair[1])
+		}
+	}
+	return headers
+}
@@ -0,0 +1,152 @@
+package input
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/config"
+)
+
+// tnet encodes a value in the tnetstring format used by mitmproxy dumps.
+func tnet(value any) string {
+	wrap := func(payload string, tag byte) string {
+		return fmt.Sprintf("%d:%s%c", len(payload), payload, tag)
+	}
+	switch v := value.(type) {
+	case []byte:
+		return wrap(string(v), ',')
+	case string:
+		return wrap(v, ';')
+	case int:
+		return wrap(fmt.Sprint(v), '#')
+	case bool:
+		return wrap(fmt.Sprint(v), '!')
+	case nil:
+		return wrap("", '~')
+	case []any:
+		var payload strings.Builder
+		for _, item := range v {
+			payload.WriteString(tnet(item))
+		}
+		return wrap(payload.String(), ']')
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		var payload strings.Builder
+		for _, key := range keys {
+			payload.WriteString(tnet(key) + tnet(v[key]))
+		}
+		return wrap(payload.String(), '}')
+	default:
+		panic(fmt.Sprintf("unsupported tnetstring value %T", value))
+	}
+}
+
+func mitmproxyFlow(method, scheme, host string, port int, path string, status int, headers [][2]string, content []byte) string {
+	var list []any
+	for _, header := range headers {
+		list = append(list, []any{[]byte(header[0]), []byte(header[1])})
+	}
+	return tnet(map[string]any{
+		"type":    "http",
+		"version": 19,
+		"request": map[string]any{
+			"method":  []byte(method),
+			"scheme":  []byte(scheme),
+			"host":    host,
+			"port":    port,
+			"path":    []byte(path),
+			"headers": []any{},
+			"content": []byte{},
+		},
+		"response": map[string]any{
+			"status_code": status,
+			"headers":     list,
+			"content":     content,
+			"timestamp":   nil,
+		},
+		"marked":    false,
+		"is_replay": nil,
+	})
+}
+
+func TestParseTNetString(t *testing.T) {
+	value, rest, err := parseTNetString([]byte(tnet(map[string]any{
+		"list": []any{1, "two", []byte("three"), true, nil},
+	}) + "tail"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(rest) != "tail" {
+		t.Fatalf("unexpected remainder %q", rest)
+	}
+	list := value.(map[string]any)["list"].([]any)
+	if list[0].(int64) != 1 || list[1].(string) != "two" || string(list[2].([]byte)) != "three" || list[3] != true || list[4] != nil {
+		t.Fatalf("unexpected list %#v", list)
+	}
+
+	for _, invalid := range []string{"5:abc,", "x:abc,", "3:abc?"} {
+		if _, _, err := parseTNetString([]byte(invalid)); err == nil {
+			t.Errorf("%q: expected an error", invalid)
+		}
+	}
+}
+
+func TestResolveTargetsMitmproxy(t *testing.T) {
+	var gzBody bytes.Buffer
+	gz := gzip.NewWriter(&gzBody)
+	gz.Write([]byte(`fetch("/api/compressed")`))
+	gz.Close()
+
+	flows := mitmproxyFlow("GET", "https", "example.com", 443, "/app.js", 200,
+		[][2]string{{"Content-Type", "application/javascript"}, {"Content-Encoding", "gzip"}}, gzBody.Bytes()) +
+		mitmproxyFlow("POST", "http", "10.0.0.5", 8080, "/api/config", 201,
+			[][2]string{{"content-type", "application/json"}}, []byte(`{"next":"/api/next"}`)) +
+		mitmproxyFlow("GET", "https", "example.com", 443, "/logo.png", 200,
+			[][2]string{{"Content-Type", "image/png"}}, []byte("\x89PNG")) +
+		tnet(map[string]any{"type": "tcp", "messages": []any{}})
+
+	file := filepath.Join(t.TempDir(), "session.flow")
+	writeFile(t, file, []byte(flows))
+
+	targets, err := ResolveTargets(config.Config{Input: file, MIMETypes: []string{"javascript", "json"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(targets) != 2 {
+		t.Fatalf("expected 2 targets, got %+v", targets)
+	}
+
+	script := targets[0]
+	if script.URL != "https://example.com/app.js" || script.Content != `fetch("/api/compressed")` || !script.Prefetched {
+		t.Fatalf("unexpected script target %+v", script)
+	}
+	if script.Method != "GET" || script.Status != 200 || script.MIMEType != "application/javascript" {
+		t.Fatalf("unexpected script metadata %+v", script)
+	}
+
+	api := targets[1]
+	if api.URL != "http://10.0.0.5:8080/api/config" || api.Method != "POST" || api.Status != 201 {
+		t.Fatalf("unexpected api target %+v", api)
+	}
+}
+
+func TestResolveTargetsMitmproxyInvalid(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "broken.flow")
+	writeFile(t, file, []byte("12:abc"))
+
+	if _, err := ResolveTargets(config.Config{Input: file}); err == nil {
+		t.Fatalf("expected truncated flow file to fail")
+	}
+	if _, err := ResolveTargets(config.Config{Input: "https://example.com", Mitmproxy: true}); err == nil {
+		t.Fatalf("expected URL input in mitmproxy mode to fail")
+	}
+}
@@ -0,0 +1,148 @@
+package input
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// zipPackageExtensions are application packages stored as plain zip archives.
+var zipPackageExtensions = []string{".apk", ".aab", ".ipa", ".xpi"}
+
+// isZipPackage reports whether name is an APK, IPA, Firefox extension or other
+// package that is a zip archive under another extension.
+func isZipPackage(lower string) bool {
+	for _, ext := range zipPackageExtensions {
+		if strings.HasSuffix(lower, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// isAppBundle reports whether name is a React Native bundle, which ships without a
+// .js extension: assets/index.android.bundle in APKs and main.jsbundle in IPAs.
+func isAppBundle(lower string) bool {
+	base := path.Base(lower)
+	return strings.HasSuffix(base, ".jsbundle") || strings.HasSuffix(base, ".android.bundle") || strings.HasSuffix(base, ".ios.bundle")
+}
+
+// hermesMagic starts React Native bundles compiled to Hermes bytecode, which hold no
+// JavaScript source to analyse.
+var hermesMagic = []byte{0xc6, 0x1f, 0xbc, 0x03, 0xc1, 0x03, 0x19, 0x1f}
+
+func isHermesBytecode(content []byte) bool {
+	return bytes.HasPrefix(content, hermesMagic)
+}
+
+// crxPayload strips the header of a Chrome extension package and returns the zip
+// archive that follows it. Both the CRX2 and CRX3 layouts are supported.
+func crxPayload(data []byte) ([]byte, error) {
+	if len(data) < 12 || string(data[:4]) != "Cr24" {
+		return nil, errors.New("invalid CRX package: missing Cr24 header")
+	}
+
+	var offset uint64
+	switch version := binary.LittleEndian.Uint32(data[4:8]); version {
+	case 2:
+		if len(data) < 16 {
+			return nil, errors.New("invalid CRX2 package: truncated header")
+		}
+		keyLen := binary.LittleEndian.Uint32(data[8:12])
+		sigLen := binary.LittleEndian.Uint32(data[12:16])
+		offset = 16 + uint64(keyLen) + uint64(sigLen)
+	case 3:
+		offset = 12 + uint64(binary.LittleEndian.Uint32(data[8:12]))
+	default:
+		return nil, errors.New("invalid CRX package: unsupported version " + strconv.Itoa(int(version)))
+	}
+
+	if offset > uint64(len(data)) {
+		return nil, errors.New("invalid CRX package: truncated header")
+	}
+	return data[offset:], nil
+}
+
+// asarEntry is a node of the JSON header of an Electron asar archive. Directories
+// have Files; regular files have an Offset, stored as a string, and a Size.
+type asarEntry struct {
+	Files    map[string]asarEntry `json:"files"`
+	Offset   string               `json:"offset"`
+	Size     int64                `json:"size"`
+	Unpacked bool                 `json:"unpacked"`
+	Link     string               `json:"link"`
+}
+
+// walkASAR visits the files of an Electron asar archive. The archive starts with a
+// Chromium pickle holding the size of the header, followed by a pickle holding the
+// JSON header; file contents follow the header.
+func walkASAR(data []byte, visit func(string, []byte) error) error {
+	if len(data) < 16 || binary.LittleEndian.Uint32(data[0:4]) != 4 {
+		return errors.New("invalid asar archive: bad header")
+	}
+
+	headerSize := uint64(binary.LittleEndian.Uint32(data[4:8]))
+	jsonSize := uint64(binary.LittleEndian.Uint32(data[12:16]))
+	if 16+jsonSize > uint64(len(data)) || 8+headerSize > uint64(len(data)) {
+		return errors.New("invalid asar archive: truncated header")
+	}
+
+	var root asarEntry
+	if err := json.Unmarshal(data[16:16+jsonSize], &root); err != nil {
+		return errors.New("invalid asar archive: " + err.Error())
+	}
+
+	base := 8 + headerSize
+	var walk func(dir string, entry asarEntry) error
+	walk = func(dir string, entry asarEntry) error {
+		names := make([]string, 0, len(entry.Files))
+		for name := range entry.Files {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			child := entry.Files[name]
+			full := path.Join(dir, name)
+
+			if child.Files != nil {
+				if err := walk(full, child); err != nil {
+					return err
+				}
+				continue
+			}
+			// Unpacked files live next to the archive, links point at other entries.
+			if child.Unpacked || child.Link != "" {
+				continue
+			}
+			if !analysableEntry(full) && archiveFormatOf(full) == archiveNone {
+				continue
+			}
+			if child.Size < 0 || child.Size > maxArchiveEntrySize {
+				continue
+			}
+
+			offset, err := strconv.ParseUint(child.Offset, 10, 64)
+			if err != nil {
+				continue
+			}
+			start := base + offset
+			end := start + uint64(child.Size)
+			if end > uint64(len(data)) || end < start {
+				continue
+			}
+
+			if err := visit(full, data[start:end]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return walk("", root)
+}
@@ -0,0 +1,195 @@
+package input
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/config"
+)
+
+func resolveContents(t *testing.T, path string) map[string]string {
+	t.Helper()
+	targets, err := ResolveTargets(config.Config{Input: path})
+	if err != nil {
+		t.Fatalf("%s: unexpected error: %v", path, err)
+	}
+	out := make(map[string]string, len(targets))
+	for _, target := range targets {
+		out[target.URL] = target.Content
+	}
+	return out
+}
+
+func writeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func TestResolveTargetsAPK(t *testing.T) {
+	hermes := append(append([]byte{}, hermesMagic...), 0, 0, 0, 0)
+	apk := zipBytes(t, map[string][]byte{
+		"assets/index.android.bundle": []byte(`fetch("/api/mobile")`),
+		"assets/other.android.bundle": hermes,
+		"assets/www/index.html":       []byte(`<script src="cordova.js"></script>`),
+		"classes.dex":                 []byte("dex\n035"),
+	})
+
+	path := filepath.Join(t.TempDir(), "app.apk")
+	writeFile(t, path, apk)
+
+	got := resolveContents(t, path)
+	if len(got) != 2 {
+		t.Fatalf("expected the JS bundle and the HTML page, got %v", got)
+	}
+	if got[path+"!/assets/index.android.bundle"] != `fetch("/api/mobile")` {
+		t.Fatalf("expected the React Native bundle, got %v", got)
+	}
+}
+
+func TestResolveTargetsIPA(t *testing.T) {
+	ipa := zipBytes(t, map[string][]byte{
+		"Payload/App.app/main.jsbundle": []byte(`fetch("/api/ios")`),
+		"Payload/App.app/Info.plist":    []byte("bplist00"),
+	})
+
+	path := filepath.Join(t.TempDir(), "App.ipa")
+	writeFile(t, path, ipa)
+
+	got := resolveContents(t, path)
+	if len(got) != 1 || got[path+"!/Payload/App.app/main.jsbundle"] != `fetch("/api/ios")` {
+		t.Fatalf("unexpected targets: %v", got)
+	}
+}
+
+func TestResolveTargetsCRX(t *testing.T) {
+	payload := zipBytes(t, map[string][]byte{
+		"manifest.json":    []byte(`{"manifest_version":3}`),
+		"js/background.js": []byte(`fetch("https://api.example.com/ext")`),
+	})
+
+	var crx3 bytes.Buffer
+	header := []byte("protobuf header")
+	crx3.WriteString("Cr24")
+	binary.Write(&crx3, binary.LittleEndian, uint32(3))
+	binary.Write(&crx3, binary.LittleEndian, uint32(len(header)))
+	crx3.Write(header)
+	crx3.Write(payload)
+
+	var crx2 bytes.Buffer
+	key, sig := []byte("public-key"), []byte("signature")
+	crx2.WriteString("Cr24")
+	binary.Write(&crx2, binary.LittleEndian, uint32(2))
+	binary.Write(&crx2, binary.LittleEndian, uint32(len(key)))
+	binary.Write(&crx2, binary.LittleEndian, uint32(len(sig)))
+	crx2.Write(key)
+	crx2.Write(sig)
+	crx2.Write(payload)
+
+	dir := t.TempDir()
+	for name, data := range map[string][]byte{"v3.crx": crx3.Bytes(), "v2.crx": crx2.Bytes()} {
+		path := filepath.Join(dir, name)
+		writeFile(t, path, data)
+
+		got := resolveContents(t, path)
+		if len(got) != 2 || got[path+"!/js/background.js"] != `fetch("https://api.example.com/ext")` {
+			t.Fatalf("%s: unexpected targets: %v", name, got)
+		}
+	}
+
+	path := filepath.Join(dir, "broken.crx")
+	writeFile(t, path, []byte("PK not a crx"))
+	if _, err := ResolveTargets(config.Config{Input: path}); err == nil {
+		t.Fatalf("expected a package without a CRX header to fail")
+	}
+}
+
+// asarBytes builds an Electron asar archive holding files.
+func asarBytes(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	type node = map[string]any
+	root := node{"files": node{}}
+	var content bytes.Buffer
+	for name, body := range files {
+		dir := root["files"].(node)
+		var parts []string
+		if parent := path.Dir(name); parent != "." {
+			parts = strings.Split(parent, "/")
+		}
+		for _, part := range parts {
+			child, ok := dir[part].(node)
+			if !ok {
+				child = node{"files": node{}}
+				dir[part] = child
+			}
+			dir = child["files"].(node)
+		}
+		dir[path.Base(name)] = node{"offset": strconv.Itoa(content.Len()), "size": len(body)}
+		content.WriteString(body)
+	}
+	root["files"].(node)["native.node"] = node{"size": 4, "unpacked": true}
+
+	header, err := json.Marshal(root)
+	if err != nil {
+		t.Fatalf("marshal asar header: %v", err)
+	}
+
+	// Header pickle: payload size, string length, string padded to 4 bytes.
+	padded := (len(header) + 3) &^ 3
+	var pickle bytes.Buffer
+	binary.Write(&pickle, binary.LittleEndian, uint32(4+padded))
+	binary.Write(&pickle, binary.LittleEndian, uint32(len(header)))
+	pickle.Write(header)
+	pickle.Write(make([]byte, padded-len(header)))
+
+	var out bytes.Buffer
+	binary.Write(&out, binary.LittleEndian, uint32(4))
+	binary.Write(&out, binary.LittleEndian, uint32(pickle.Len()))
+	out.Write(pickle.Bytes())
+	out.Write(content.Bytes())
+	return out.Bytes()
+}
+
+func TestResolveTargetsASAR(t *testing.T) {
+	asar := asarBytes(t, map[string]string{
+		"package.json":          `{"main":"dist/main.js"}`,
+		"dist/main.js":          `fetch("/api/desktop")`,
+		"dist/renderer/app.mjs": `import("./lazy.mjs")`,
+		"assets/icon.png":       "\x89PNG",
+	})
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.asar")
+	writeFile(t, path, asar)
+
+	got := resolveContents(t, path)
+	want := map[string]string{
+		path + "!/package.json":          `{"main":"dist/main.js"}`,
+		path + "!/dist/main.js":          `fetch("/api/desktop")`,
+		path + "!/dist/renderer/app.mjs": `import("./lazy.mjs")`,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d targets, got %v", len(want), got)
+	}
+	for name, content := range want {
+		if got[name] != content {
+			t.Fatalf("%s = %q, want %q", name, got[name], content)
+		}
+	}
+
+	// Electron apps are often shipped zipped, with the asar inside.
+	zipped := filepath.Join(dir, "app.zip")
+	writeFile(t, zipped, zipBytes(t, map[string][]byte{"resources/app.asar": asar}))
+	if got := resolveContents(t, zipped); got[zipped+"!/resources/app.asar!/dist/main.js"] != `fetch("/api/desktop")` {
+		t.Fatalf("expected nested asar entries, got %v", got)
+	}
+}
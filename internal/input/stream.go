@@ -0,0 +1,210 @@
+package input
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/model"
+)
+
+// listSniffSize is how much of a file is inspected to decide whether it is a target list.
+const listSniffSize = 64 << 10
+
+// Stream yields the targets of an input one at a time. Next is not safe for concurrent
+// use; Lines may be called from any goroutine.
+type Stream struct {
+	// targets holds the remaining targets of inputs resolved up front.
+	targets []model.Target
+
+	// next is set for captures that are decoded one record at a time.
+	next func() (model.Target, error)
+
+	// reader is set for target lists, which are parsed line by line.
+	reader   *bufio.Reader
+	closer   io.Closer
+	baseDir  string
+	emptyErr error
+	dedup    *targetDedup
+	emitted  bool
+	lines    atomic.Int64
+}
+
+func newSliceStream(targets []model.Target, err error) (*Stream, error) {
+	if err != nil {
+		return nil, err
+	}
+	return &Stream{targets: targets}, nil
+}
+
+// newRecordStream reads a capture one record at a time: next returns the following
+// target, or io.EOF once the capture is exhausted. closer is closed with the stream.
+func newRecordStream(next func() (model.Target, error), closer io.Closer) *Stream {
+	return &Stream{next: next, closer: closer}
+}
+
+// newListStream reads a target list from r. Relative file paths are resolved against
+// baseDir, and emptyErr, when set, is returned if the list holds no target at all.
+func newListStream(r io.Reader, closer io.Closer, baseDir string, emptyErr error) *Stream {
+	reader, ok := r.(*bufio.Reader)
+	if !ok {
+		reader = bufio.NewReader(r)
+	}
+	return &Stream{
+		reader:   reader,
+		closer:   closer,
+		baseDir:  baseDir,
+		emptyErr: emptyErr,
+		dedup:    newTargetDedup(),
+	}
+}
+
+// openListFile opens path as a target list when its first lines name URLs or files.
+func openListFile(path string) (*Stream, bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, false, err
+	}
+
+	reader := bufio.NewReaderSize(file, listSniffSize)
+	head, err := reader.Peek(listSniffSize)
+	if err != nil && !errors.Is(err, io.EOF) {
+		file.Close()
+		return nil, false, err
+	}
+
+	baseDir := filepath.Dir(path)
+	for _, line := range strings.Split(string(head), "\n") {
+		if _, ok, err := parseTargetLine(line, baseDir); err != nil {
+			file.Close()
+			return nil, false, err
+		} else if ok {
+			return newListStream(reader, file, baseDir, nil), true, nil
+		}
+	}
+
+	file.Close()
+	return nil, false, nil
+}
+
+// IsList reports whether the stream reads a target list.
+func (s *Stream) IsList() bool {
+	return s.reader != nil
+}
+
+// Lines returns the number of lines read so far from a target list.
+func (s *Stream) Lines() int64 {
+	return s.lines.Load()
+}
+
+// Next returns the next target, or io.EOF once the input is exhausted. Targets read
+// from a list are deduplicated.
+func (s *Stream) Next() (model.Target, error) {
+	if s.next != nil {
+		return s.next()
+	}
+	if s.reader == nil {
+		if len(s.targets) == 0 {
+			return model.Target{}, io.EOF
+		}
+		target := s.targets[0]
+		s.targets[0] = model.Target{}
+		s.targets = s.targets[1:]
+		return target, nil
+	}
+
+	for {
+		line, err := s.reader.ReadString('\n')
+		if line != "" {
+			s.lines.Add(1)
+			target, ok, parseErr := parseTargetLine(line, s.baseDir)
+			if parseErr != nil {
+				return model.Target{}, parseErr
+			}
+			if ok && s.dedup.add(target.URL) {
+				s.emitted = true
+				return target, nil
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			if !s.emitted && s.emptyErr != nil {
+				emptyErr := s.emptyErr
+				s.emptyErr = nil
+				return model.Target{}, emptyErr
+			}
+			return model.Target{}, io.EOF
+		}
+		if err != nil {
+			return model.Target{}, err
+		}
+	}
+}
+
+// Close releases the file a target list or capture is read from.
+func (s *Stream) Close() error {
+	if s.closer == nil {
+		return nil
+	}
+	return s.closer.Close()
+}
+
+// parseTargetLine parses a line of a target list: a URL, optionally prefixed with
+// "view-source:", or the path of an existing file. Blank lines, comments and paths
+// that do not exist are skipped.
+func parseTargetLine(line, baseDir string) (model.Target, bool, error) {
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+		return model.Target{}, false, nil
+	}
+
+	if strings.HasPrefix(trimmed, "view-source:") {
+		trimmed = strings.TrimSpace(trimmed[12:])
+		if trimmed == "" {
+			return model.Target{}, false, nil
+		}
+	}
+
+	if isURLInput(trimmed) {
+		return model.Target{URL: trimmed}, true, nil
+	}
+
+	candidate := trimmed
+	if !filepath.IsAbs(candidate) {
+		candidate = filepath.Join(baseDir, candidate)
+	}
+
+	info, err := os.Stat(candidate)
+	if err != nil || info.IsDir() {
+		return model.Target{}, false, nil
+	}
+
+	abs, err := filepath.Abs(candidate)
+	if err != nil {
+		return model.Target{}, false, err
+	}
+	return model.Target{URL: "file://" + abs}, true, nil
+}
+
+// parseTargetsFromReader reads a whole target list.
+func parseTargetsFromReader(r io.Reader, baseDir string) ([]model.Target, error) {
+	return newListStream(r, nil, baseDir, nil).all()
+}
+
+// all reads the remaining targets of the stream.
+func (s *Stream) all() ([]model.Target, error) {
+	var targets []model.Target
+	for {
+		target, err := s.Next()
+		if errors.Is(err, io.EOF) {
+			return targets, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, target)
+	}
+}
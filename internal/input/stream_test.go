@@ -0,0 +1,124 @@
+package input
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/config"
+)
+
+func TestOpenStreamsTargetList(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "local.js"), []byte("console.log('local');"))
+
+	var list strings.Builder
+	list.WriteString("# gau output\n")
+	for i := 0; i < 1000; i++ {
+		fmt.Fprintf(&list, "https://example.com/%d.js\n", i%10)
+	}
+	list.WriteString("local.js\n")
+	listPath := filepath.Join(dir, "urls.txt")
+	writeFile(t, listPath, []byte(list.String()))
+
+	stream, err := Open(config.Config{Input: listPath})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer stream.Close()
+	if !stream.IsList() {
+		t.Fatalf("expected a target list stream")
+	}
+
+	first, err := stream.Next()
+	if err != nil || first.URL != "https://example.com/0.js" {
+		t.Fatalf("unexpected first target %+v (%v)", first, err)
+	}
+	if lines := stream.Lines(); lines != 2 {
+		t.Fatalf("expected the list to be read lazily, %d lines read", lines)
+	}
+
+	targets, err := stream.all()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(targets) != 10 {
+		t.Fatalf("expected duplicates to be dropped, got %d targets", len(targets))
+	}
+	if last := targets[len(targets)-1].URL; !strings.HasPrefix(last, "file://") {
+		t.Fatalf("expected the local file last, got %q", last)
+	}
+	if lines := stream.Lines(); lines != 1002 {
+		t.Fatalf("expected 1002 lines read, got %d", lines)
+	}
+	if _, err := stream.Next(); !errors.Is(err, io.EOF) {
+		t.Fatalf("expected io.EOF after the list, got %v", err)
+	}
+}
+
+func TestOpenSingleTarget(t *testing.T) {
+	stream, err := Open(config.Config{Input: "https://example.com/app.js"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stream.IsList() {
+		t.Fatalf("a URL input is not a list")
+	}
+	if target, err := stream.Next(); err != nil || target.URL != "https://example.com/app.js" {
+		t.Fatalf("unexpected target %+v (%v)", target, err)
+	}
+	if _, err := stream.Next(); !errors.Is(err, io.EOF) {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+}
+
+func TestListStreamEmptyError(t *testing.T) {
+	emptyErr := errors.New("no targets")
+	stream := newListStream(strings.NewReader("# nothing\n\n/does/not/exist.js\n"), nil, t.TempDir(), emptyErr)
+
+	if _, err := stream.Next(); !errors.Is(err, emptyErr) {
+		t.Fatalf("expected the empty list error, got %v", err)
+	}
+	if _, err := stream.Next(); !errors.Is(err, io.EOF) {
+		t.Fatalf("expected io.EOF after the error, got %v", err)
+	}
+}
+
+func TestTargetDedup(t *testing.T) {
+	dedup := newTargetDedup()
+	for i := 0; i <= exactDedupLimit; i++ {
+		if !dedup.add(fmt.Sprintf("https://example.com/%d", i)) {
+			t.Fatalf("value %d reported as seen", i)
+		}
+	}
+	if dedup.bloom == nil || dedup.exact != nil {
+		t.Fatalf("expected the exact set to be replaced by a Bloom filter")
+	}
+
+	if dedup.add("https://example.com/0") || dedup.add(fmt.Sprintf("https://example.com/%d", exactDedupLimit)) {
+		t.Fatalf("expected values remembered by the Bloom filter to be reported as seen")
+	}
+
+	fresh := 0
+	for i := 0; i < 1000; i++ {
+		if dedup.add(fmt.Sprintf("https://other.example/%d", i)) {
+			fresh++
+		}
+	}
+	if fresh < 990 {
+		t.Fatalf("too many false positives: only %d of 1000 new values accepted", fresh)
+	}
+}
+
+func TestTargetDedupSeparatesParts(t *testing.T) {
+	dedup := newTargetDedup()
+	if !dedup.add("GET", "https://example.com/app.js", "a") || !dedup.add("GET", "https://example.com/app.js", "b") {
+		t.Fatalf("expected targets with different content to be distinct")
+	}
+	if !dedup.add("ab", "c") || !dedup.add("a", "bc") {
+		t.Fatalf("expected the parts of a target to be kept apart")
+	}
+}
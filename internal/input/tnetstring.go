@@ -0,0 +1,125 @@
+package input
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// maxTNetStringSize bounds a single top-level tnetstring, which protects against
+// corrupt length prefixes.
+const maxTNetStringSize = 256 << 20
+
+// readTNetString reads the next top-level value of a tnetstring stream, or io.EOF once
+// the stream is exhausted. Byte strings decode to []byte, unicode strings to string,
+// lists to []any and dictionaries to map[string]any.
+func readTNetString(r *bufio.Reader) (any, error) {
+	prefix, err := r.ReadString(':')
+	if err != nil {
+		if errors.Is(err, io.EOF) && strings.TrimSpace(prefix) == "" {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("invalid tnetstring: %w", io.ErrUnexpectedEOF)
+	}
+
+	length, err := strconv.Atoi(strings.TrimSpace(strings.TrimSuffix(prefix, ":")))
+	if err != nil || length < 0 || length > maxTNetStringSize {
+		return nil, fmt.Errorf("invalid tnetstring length %q", strings.TrimSuffix(prefix, ":"))
+	}
+
+	data := make([]byte, length+1)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, fmt.Errorf("invalid tnetstring: %w", io.ErrUnexpectedEOF)
+	}
+	return tnetValue(data[:length], data[length])
+}
+
+// parseTNetString decodes the value at the start of data and returns the remaining
+// bytes.
+func parseTNetString(data []byte) (any, []byte, error) {
+	colon := -1
+	for idx, b := range data {
+		if b == ':' {
+			colon = idx
+			break
+		}
+		if b < '0' || b > '9' || idx > 10 {
+			break
+		}
+	}
+	if colon <= 0 {
+		return nil, nil, errors.New("invalid tnetstring: missing length")
+	}
+
+	length, err := strconv.Atoi(string(data[:colon]))
+	if err != nil || colon+1+length >= len(data) {
+		return nil, nil, errors.New("invalid tnetstring: truncated value")
+	}
+
+	payload := data[colon+1 : colon+1+length]
+	value, err := tnetValue(payload, data[colon+1+length])
+	if err != nil {
+		return nil, nil, err
+	}
+	return value, data[colon+2+length:], nil
+}
+
+func tnetValue(payload []byte, tag byte) (any, error) {
+	switch tag {
+	case ',':
+		return payload, nil
+	case ';':
+		return string(payload), nil
+	case '#':
+		return strconv.ParseInt(string(payload), 10, 64)
+	case '^':
+		return strconv.ParseFloat(string(payload), 64)
+	case '!':
+		return string(payload) == "true", nil
+	case '~':
+		return nil, nil
+	case ']':
+		var list []any
+		for len(payload) > 0 {
+			value, rest, err := parseTNetString(payload)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+			payload = rest
+		}
+		return list, nil
+	case '}':
+		dict := make(map[string]any)
+		for len(payload) > 0 {
+			key, rest, err := parseTNetString(payload)
+			if err != nil {
+				return nil, err
+			}
+			value, rest, err := parseTNetString(rest)
+			if err != nil {
+				return nil, err
+			}
+			dict[tnetText(key)] = value
+			payload = rest
+		}
+		return dict, nil
+	default:
+		return nil, fmt.Errorf("invalid tnetstring type %q", tag)
+	}
+}
+
+// tnetText returns a byte or unicode string value as text.
+func tnetText(value any) string {
+	switch v := value.(type) {
+	case []byte:
+		return string(v)
+	case string:
+		return v
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,182 @@
+package input
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/config"
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/model"
+)
+
+// skippedDirs are version control directories that never hold analysable sources.
+var skippedDirs = map[string]struct{}{".git": {}, ".hg": {}, ".svn": {}}
+
+// walkOptions control which files of a directory input become targets.
+type walkOptions struct {
+	include []globPattern
+	exclude []globPattern
+	maxSize int64 // 0 disables the limit
+}
+
+func newWalkOptions(cfg config.Config) (walkOptions, error) {
+	opts := walkOptions{maxSize: cfg.MaxFileSize}
+	for _, raw := range cfg.Include {
+		pattern, ok := compileGlob(raw)
+		if !ok || pattern.negate {
+			return walkOptions{}, fmt.Errorf("invalid --include pattern %q", raw)
+		}
+		opts.include = append(opts.include, pattern)
+	}
+	for _, raw := range cfg.Exclude {
+		pattern, ok := compileGlob(raw)
+		if !ok || pattern.negate {
+			return walkOptions{}, fmt.Errorf("invalid --exclude pattern %q", raw)
+		}
+		opts.exclude = append(opts.exclude, pattern)
+	}
+	return opts, nil
+}
+
+// wanted reports whether the file at rel should be analysed. Without include patterns
+// every script, HTML and JSON file is.
+func (o walkOptions) wanted(rel string) bool {
+	if len(o.include) == 0 {
+		return analysableEntry(rel)
+	}
+	for _, pattern := range o.include {
+		if pattern.match(rel, false) {
+			return true
+		}
+	}
+	return false
+}
+
+func (o walkOptions) excluded(rel string, isDir bool) bool {
+	for _, pattern := range o.exclude {
+		if pattern.match(rel, isDir) {
+			return true
+		}
+	}
+	return false
+}
+
+// walkDirectory returns the files below root as file:// targets. Ignore files are
+// honoured in every directory, and symbolic links are only followed when they resolve
+// inside root, so links cannot escape the tree or make the walk loop.
+func walkDirectory(root string, opts walkOptions) ([]model.Target, error) {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	realRoot, err := filepath.EvalSymlinks(abs)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &directoryWalker{
+		opts:     opts,
+		realRoot: realRoot,
+		visited:  map[string]struct{}{realRoot: {}},
+	}
+	if err := w.walk(abs, "", nil); err != nil {
+		return nil, err
+	}
+
+	if len(w.targets) == 0 {
+		return nil, fmt.Errorf("%s: directory contains no files to analyse", root)
+	}
+	return w.targets, nil
+}
+
+type directoryWalker struct {
+	opts     walkOptions
+	realRoot string
+	visited  map[string]struct{} // real paths of the directories already walked
+	targets  []model.Target
+}
+
+func (w *directoryWalker) walk(dir, rel string, stack []ignoreRules) error {
+	for _, name := range ignoreFileNames {
+		if rules, ok := loadIgnoreFile(filepath.Join(dir, name), rel); ok {
+			stack = append(stack[:len(stack):len(stack)], rules)
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if rel == "" {
+			return err
+		}
+		// An unreadable subdirectory should not hide the rest of the tree.
+		return nil
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		path := filepath.Join(dir, name)
+		entryRel := name
+		if rel != "" {
+			entryRel = rel + "/" + name
+		}
+
+		info, err := w.stat(path, entry)
+		if err != nil {
+			continue
+		}
+
+		if info.IsDir() {
+			if _, skip := skippedDirs[name]; skip {
+				continue
+			}
+			if ignored(stack, entryRel, true) || w.opts.excluded(entryRel, true) {
+				continue
+			}
+			real, err := filepath.EvalSymlinks(path)
+			if err != nil {
+				continue
+			}
+			if _, seen := w.visited[real]; seen {
+				continue
+			}
+			w.visited[real] = struct{}{}
+			if err := w.walk(path, entryRel, stack); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !info.Mode().IsRegular() {
+			continue
+		}
+		if ignored(stack, entryRel, false) || w.opts.excluded(entryRel, false) || !w.opts.wanted(entryRel) {
+			continue
+		}
+		if w.opts.maxSize > 0 && info.Size() > w.opts.maxSize {
+			continue
+		}
+
+		w.targets = append(w.targets, model.Target{URL: "file://" + path})
+	}
+
+	return nil
+}
+
+// stat returns the file information of an entry, following symbolic links that
+// resolve inside the walk root.
+func (w *directoryWalker) stat(path string, entry os.DirEntry) (os.FileInfo, error) {
+	if entry.Type()&os.ModeSymlink == 0 {
+		return entry.Info()
+	}
+
+	real, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return nil, err
+	}
+	if real != w.realRoot && !strings.HasPrefix(real, w.realRoot+string(filepath.Separator)) {
+		return nil, errors.New("symbolic link points outside the input directory")
+	}
+	return os.Stat(real)
+}
@@ -0,0 +1,160 @@
+package input
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/config"
+)
+
+func buildTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir %s: %v", name, err)
+		}
+		writeFile(t, path, []byte(content))
+	}
+	return root
+}
+
+func walkedFiles(t *testing.T, cfg config.Config) []string {
+	t.Helper()
+	targets, err := ResolveTargets(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	root, err := filepath.EvalSymlinks(cfg.Input)
+	if err != nil {
+		t.Fatalf("eval root: %v", err)
+	}
+	var files []string
+	for _, target := range targets {
+		if target.Prefetched || !strings.HasPrefix(target.URL, "file://") {
+			t.Fatalf("unexpected target %+v", target)
+		}
+		rel, err := filepath.Rel(root, strings.TrimPrefix(target.URL, "file://"))
+		if err != nil {
+			t.Fatalf("rel: %v", err)
+		}
+		files = append(files, filepath.ToSlash(rel))
+	}
+	sort.Strings(files)
+	return files
+}
+
+func TestResolveTargetsDirectory(t *testing.T) {
+	root := buildTree(t, map[string]string{
+		"index.html":               "<script src=/app.js></script>",
+		"static/js/app.js":         "fetch('/api')",
+		"static/js/app.js.map":     "{}",
+		"static/css/site.css":      "body{}",
+		"config/routes.json":       "{}",
+		"node_modules/lib/lib.js":  "x",
+		"dist/bundle.min.js":       "x",
+		"dist/keep.js":             "x",
+		"logs/debug.log":           "x",
+		"src/.gitignore":           "generated/\n",
+		"src/generated/api.js":     "x",
+		"src/main.ts":              "x",
+		".git/hooks/pre-commit.js": "x",
+		".gitignore":               "node_modules\n/dist/*\n!/dist/keep.js\n*.log\n",
+	})
+
+	got := walkedFiles(t, config.Config{Input: root})
+	want := []string{"config/routes.json", "dist/keep.js", "index.html", "src/main.ts", "static/js/app.js"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected files:\n got %v\nwant %v", got, want)
+	}
+}
+
+func TestResolveTargetsDirectoryPatterns(t *testing.T) {
+	root := buildTree(t, map[string]string{
+		"app.js":            "x",
+		"vendor/jquery.js":  "x",
+		"assets/data.json":  "x",
+		"assets/deep/a.map": "x",
+		"large.js":          strings.Repeat("x", 2048),
+	})
+
+	got := walkedFiles(t, config.Config{
+		Input:       root,
+		Include:     []string{"*.js", "assets/**/*.map"},
+		Exclude:     []string{"vendor/"},
+		MaxFileSize: 1024,
+	})
+	want := []string{"app.js", "assets/deep/a.map"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected files:\n got %v\nwant %v", got, want)
+	}
+
+	if _, err := ResolveTargets(config.Config{Input: root, Include: []string{"*.none"}}); err == nil {
+		t.Fatalf("expected an error when no file matches")
+	}
+	if _, err := ResolveTargets(config.Config{Input: root, Exclude: []string{"!app.js"}}); err == nil {
+		t.Fatalf("expected negated --exclude pattern to be rejected")
+	}
+}
+
+func TestResolveTargetsDirectorySymlinks(t *testing.T) {
+	outside := buildTree(t, map[string]string{"secret.js": "x"})
+	root := buildTree(t, map[string]string{"src/app.js": "x"})
+
+	links := map[string]string{
+		"loop":        root,
+		"src/alias":   filepath.Join(root, "src"),
+		"shared.js":   filepath.Join(root, "src", "app.js"),
+		"escape":      outside,
+		"escape.js":   filepath.Join(outside, "secret.js"),
+		"dangling.js": filepath.Join(root, "missing.js"),
+	}
+	for name, target := range links {
+		if err := os.Symlink(target, filepath.Join(root, filepath.FromSlash(name))); err != nil {
+			t.Skipf("symlinks unavailable: %v", err)
+		}
+	}
+
+	got := walkedFiles(t, config.Config{Input: root})
+	want := []string{"shared.js", "src/app.js"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected files:\n got %v\nwant %v", got, want)
+	}
+}
+
+func TestCompileGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		isDir   bool
+		want    bool
+	}{
+		{"*.js", "a/b/app.js", false, true},
+		{"*.js", "a/b/app.jsx", false, false},
+		{"/dist", "dist", true, true},
+		{"/dist", "src/dist", true, false},
+		{"build/", "build", false, false},
+		{"build/", "pkg/build", true, true},
+		{"src/**/*.js", "src/app.js", false, true},
+		{"src/**/*.js", "src/a/b/app.js", false, true},
+		{"**/test", "a/test", true, true},
+		{"chunk-[0-9].js", "chunk-7.js", false, true},
+		{"chunk-[!0-9].js", "chunk-7.js", false, false},
+		{"file?.js", "file1.js", false, true},
+		{`\!important.js`, "!important.js", false, true},
+	}
+
+	for _, tt := range tests {
+		pattern, ok := compileGlob(tt.pattern)
+		if !ok {
+			t.Fatalf("%q: failed to compile", tt.pattern)
+		}
+		if got := pattern.match(tt.path, tt.isDir); got != tt.want {
+			t.Errorf("%q on %q (dir=%v): got %v, want %v", tt.pattern, tt.path, tt.isDir, got, tt.want)
+		}
+	}
+}
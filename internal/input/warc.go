@@ -0,0 +1,203 @@
+package input
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/model"
+)
+
+// WARC record types analysed as targets.
+const (
+	warcResponse = "response"
+	warcResource = "resource"
+)
+
+// warcRecord is a single record of a WARC archive. Block is only valid until the next
+// call to warcReader.Next.
+type warcRecord struct {
+	Type          string
+	TargetURI     string
+	Date          string
+	ContentType   string
+	PayloadDigest string
+	Block         io.Reader
+}
+
+// warcReader reads the records of a WARC or WARC.gz archive one at a time, so archives
+// of any size are processed in constant memory.
+type warcReader struct {
+	r       *bufio.Reader
+	tp      *textproto.Reader
+	pending io.Reader
+}
+
+func newWARCReader(r io.Reader) (*warcReader, error) {
+	buffered := bufio.NewReader(r)
+	if magic, err := buffered.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		// WARC.gz archives compress every record as its own gzip member; the reader
+		// decompresses them as one continuous stream.
+		gz, err := gzip.NewReader(buffered)
+		if err != nil {
+			return nil, err
+		}
+		buffered = bufio.NewReader(gz)
+	}
+	return &warcReader{r: buffered, tp: textproto.NewReader(buffered)}, nil
+}
+
+// Next returns the next record, or io.EOF once the archive is exhausted.
+func (w *warcReader) Next() (warcRecord, error) {
+	if w.pending != nil {
+		if _, err := io.Copy(io.Discard, w.pending); err != nil {
+			return warcRecord{}, err
+		}
+		w.pending = nil
+	}
+
+	// Records are separated by blank lines.
+	var version string
+	for {
+		line, err := w.tp.ReadLine()
+		if err != nil {
+			return warcRecord{}, err
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			version = line
+			break
+		}
+	}
+	if !strings.HasPrefix(version, "WARC/") {
+		return warcRecord{}, fmt.Errorf("invalid WARC record: unexpected line %q", version)
+	}
+
+	header, err := w.tp.ReadMIMEHeader()
+	if err != nil && !errors.Is(err, io.EOF) {
+		return warcRecord{}, fmt.Errorf("invalid WARC record header: %w", err)
+	}
+
+	length, err := strconv.ParseInt(strings.TrimSpace(header.Get("Content-Length")), 10, 64)
+	if err != nil || length < 0 {
+		return warcRecord{}, fmt.Errorf("invalid WARC record: bad Content-Length %q", header.Get("Content-Length"))
+	}
+
+	block := io.LimitReader(w.r, length)
+	w.pending = block
+
+	return warcRecord{
+		Type:          strings.ToLower(header.Get("WARC-Type")),
+		TargetURI:     strings.Trim(strings.TrimSpace(header.Get("WARC-Target-URI")), "<>"),
+		Date:          header.Get("WARC-Date"),
+		ContentType:   header.Get("Content-Type"),
+		PayloadDigest: header.Get("WARC-Payload-Digest"),
+		Block:         block,
+	}, nil
+}
+
+// isWARCFile reports whether path names a WARC archive.
+func isWARCFile(path string) bool {
+	lower := strings.ToLower(path)
+	return strings.HasSuffix(lower, ".warc") || strings.HasSuffix(lower, ".warc.gz")
+}
+
+// openWARCFile reads the response and resource records of a WARC archive one at a time
+// and yields them as targets named after their target URI. Only a digest of every
+// record is kept to drop duplicates, so archives of any size are read in bounded
+// memory.
+func openWARCFile(path string, mimeTypes []string) (*Stream, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := newWARCReader(file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("invalid WARC file: %w", err)
+	}
+
+	seen := newTargetDedup()
+	next := func() (model.Target, error) {
+		for {
+			record, err := reader.Next()
+			if errors.Is(err, io.EOF) {
+				return model.Target{}, io.EOF
+			}
+			if err != nil {
+				return model.Target{}, fmt.Errorf("%s: %w", path, err)
+			}
+
+			target, ok, err := warcTarget(record, mimeTypes)
+			if err != nil {
+				return model.Target{}, fmt.Errorf("%s: %w", path, err)
+			}
+			if !ok {
+				continue
+			}
+
+			// The payload digest identifies the body without hashing it again.
+			fresh := false
+			if record.PayloadDigest != "" {
+				fresh = seen.add(target.URL, record.PayloadDigest)
+			} else {
+				fresh = seen.addCapture(target)
+			}
+			if fresh {
+				return target, nil
+			}
+		}
+	}
+
+	return newRecordStream(next, file), nil
+}
+
+// warcTarget converts a record into a target. Records of other types, without a
+// target URI or body, or whose MIME type is not allowed are skipped.
+func warcTarget(record warcRecord, mimeTypes []string) (model.Target, bool, error) {
+	if record.TargetURI == "" {
+		return model.Target{}, false, nil
+	}
+
+	var target model.Target
+	switch record.Type {
+	case warcResponse:
+		if !strings.HasPrefix(strings.ToLower(record.ContentType), "application/http") {
+			return model.Target{}, false, nil
+		}
+		resp, err := readCapturedResponse(record.Block)
+		if err != nil || !mimeAllowed(resp.MIMEType, record.TargetURI, mimeTypes) {
+			return model.Target{}, false, nil
+		}
+		target = model.Target{Content: string(resp.Body), Status: resp.Status, MIMEType: resp.MIMEType}
+	case warcResource:
+		if !mimeAllowed(record.ContentType, record.TargetURI, mimeTypes) {
+			return model.Target{}, false, nil
+		}
+		body, err := io.ReadAll(record.Block)
+		if err != nil {
+			return model.Target{}, false, err
+		}
+		target = model.Target{Content: string(body), MIMEType: record.ContentType}
+	default:
+		return model.Target{}, false, nil
+	}
+
+	if strings.TrimSpace(target.Content) == "" {
+		return model.Target{}, false, nil
+	}
+
+	target.URL = record.TargetURI
+	target.Prefetched = true
+	if captured, err := time.Parse(time.RFC3339, record.Date); err == nil {
+		target.CapturedAt = captured.UTC().Format(time.RFC3339)
+	}
+	return target, true, nil
+}
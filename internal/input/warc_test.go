@@ -0,0 +1,144 @@
+package input
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/config"
+)
+
+// testWARCDate is the capture time of records whose date does not matter.
+const testWARCDate = "2024-05-01T10:00:00Z"
+
+// warcRecordBytes formats a WARC record. The payload digest header is left out when
+// digest is empty.
+func warcRecordBytes(recordType, uri, date, digest, contentType, block string) string {
+	var digestHeader string
+	if digest != "" {
+		digestHeader = "WARC-Payload-Digest: " + digest + "\r\n"
+	}
+	return fmt.Sprintf("WARC/1.1\r\nWARC-Type: %s\r\nWARC-Target-URI: %s\r\nWARC-Date: %s\r\n%sContent-Type: %s\r\nContent-Length: %d\r\n\r\n%s\r\n\r\n",
+		recordType, uri, date, digestHeader, contentType, len(block), block)
+}
+
+func testWARCRecords() []string {
+	var gzBody bytes.Buffer
+	gz := gzip.NewWriter(&gzBody)
+	gz.Write([]byte(`fetch("/api/compressed")`))
+	gz.Close()
+
+	return []string{
+		warcRecordBytes("warcinfo", "", testWARCDate, "", "application/warc-fields", "software: test\r\n"),
+		warcRecordBytes("request", "https://example.com/app.js", testWARCDate, "", "application/http; msgtype=request",
+			"GET /app.js HTTP/1.1\r\nHost: example.com\r\n\r\n"),
+		warcRecordBytes("response", "https://example.com/app.js", testWARCDate, "", "application/http; msgtype=response",
+			"HTTP/1.1 200 OK\r\nContent-Type: application/javascript\r\nTransfer-Encoding: chunked\r\n\r\n11\r\nfetch('/api/chunk\r\n4\r\ned')\r\n0\r\n\r\n"),
+		warcRecordBytes("response", "<https://example.com/gz.js>", testWARCDate, "", "application/http; msgtype=response",
+			"HTTP/1.1 200 OK\r\nContent-Type: text/javascript\r\nContent-Encoding: gzip\r\n\r\n"+gzBody.String()),
+		warcRecordBytes("response", "https://example.com/logo.png", testWARCDate, "", "application/http; msgtype=response",
+			"HTTP/1.1 200 OK\r\nContent-Type: image/png\r\n\r\n\x89PNG"),
+		warcRecordBytes("resource", "https://example.com/config.json", testWARCDate, "", "application/json", `{"api":"/api/config"}`),
+	}
+}
+
+func TestResolveTargetsWARC(t *testing.T) {
+	dir := t.TempDir()
+
+	var plain bytes.Buffer
+	var compressed bytes.Buffer
+	for _, record := range testWARCRecords() {
+		plain.WriteString(record)
+
+		// Every record is its own gzip member, as in real WARC.gz files.
+		gz := gzip.NewWriter(&compressed)
+		gz.Write([]byte(record))
+		gz.Close()
+	}
+
+	files := map[string][]byte{
+		filepath.Join(dir, "crawl.warc"):    plain.Bytes(),
+		filepath.Join(dir, "crawl.warc.gz"): compressed.Bytes(),
+	}
+
+	for path, data := range files {
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatalf("write warc file: %v", err)
+		}
+
+		targets, err := ResolveTargets(config.Config{Input: path, MIMETypes: []string{"javascript", "json"}})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", path, err)
+		}
+		if len(targets) != 3 {
+			t.Fatalf("%s: expected 3 targets, got %+v", path, targets)
+		}
+
+		want := []struct {
+			url     string
+			content string
+			status  int
+		}{
+			{"https://example.com/app.js", "fetch('/api/chunked')", 200},
+			{"https://example.com/gz.js", `fetch("/api/compressed")`, 200},
+			{"https://example.com/config.json", `{"api":"/api/config"}`, 0},
+		}
+		for idx, w := range want {
+			got := targets[idx]
+			if got.URL != w.url || got.Content != w.content || got.Status != w.status || !got.Prefetched {
+				t.Fatalf("%s: target %d = %+v, want %+v", path, idx, got, w)
+			}
+		}
+	}
+}
+
+func TestResolveTargetsWARCInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "broken.warc")
+	if err := os.WriteFile(path, []byte("not a warc file\n"), 0o644); err != nil {
+		t.Fatalf("write warc file: %v", err)
+	}
+
+	if _, err := ResolveTargets(config.Config{Input: path}); err == nil {
+		t.Fatalf("expected an invalid archive to fail")
+	}
+}
+
+func TestOpenWARCFileStreamsAndDedupsByDigest(t *testing.T) {
+	record := func(digest, body string) string {
+		block := "HTTP/1.1 200 OK\r\nContent-Type: application/javascript\r\n\r\n" + body
+		return warcRecordBytes("response", "https://example.com/app.js", testWARCDate, digest, "application/http; msgtype=response", block)
+	}
+
+	path := filepath.Join(t.TempDir(), "crawl.warc")
+	data := record("sha1:AAAA", "fetch('/api/v1')") + record("sha1:AAAA", "fetch('/api/v1')") + record("sha1:BBBB", "fetch('/api/v2')") + "WARC/broken"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("write warc file: %v", err)
+	}
+
+	stream, err := openWARCFile(path, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer stream.Close()
+
+	var contents []string
+	for {
+		target, err := stream.Next()
+		if err != nil {
+			// The truncated record fails only once the records before it were read.
+			if len(contents) != 2 || contents[0] != "fetch('/api/v1')" || contents[1] != "fetch('/api/v2')" {
+				t.Fatalf("expected both distinct records before the error, got %v", contents)
+			}
+			if errors.Is(err, io.EOF) {
+				t.Fatalf("expected the truncated record to fail")
+			}
+			return
+		}
+		contents = append(contents, target.Content)
+	}
+}
@@ -0,0 +1,150 @@
+package input
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/model"
+)
+
+// zapSeparator starts every message of a ZAP "Export Messages to File" dump, e.g.
+// "==== 12 ==========".
+var zapSeparator = regexp.MustCompile(`^==== \d+ =+\r?\n?$`)
+
+// isZAPFile reports whether path holds a ZAP message export, judging by its first line.
+func isZAPFile(path string) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	line, err := bufio.NewReader(file).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false
+	}
+	return zapSeparator.MatchString(line)
+}
+
+// parseZAPFile reads a ZAP "Export Messages to File" dump. Each message holds the raw
+// request, which names the absolute URL, followed by the raw response.
+func parseZAPFile(path string, mimeTypes []string) ([]model.Target, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	seen := newTargetDedup()
+
+	var (
+		targets []model.Target
+		message bytes.Buffer
+		index   int
+		started bool
+	)
+	flush := func() {
+		if !started {
+			return
+		}
+		target, err := zapTarget(message.Bytes())
+		message.Reset()
+		if err != nil {
+			fmt.Fprintf(warningOut, "Warning: skipping ZAP message %d: %v\n", index, err)
+			return
+		}
+		if strings.TrimSpace(target.Content) == "" || !mimeAllowed(target.MIMEType, target.URL, mimeTypes) {
+			return
+		}
+
+		if !seen.addCapture(target) {
+			return
+		}
+		targets = append(targets, target)
+	}
+
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadString('\n')
+		if len(line) > 0 {
+			if zapSeparator.MatchString(line) {
+				flush()
+				index++
+				started = true
+			} else if started {
+				message.WriteString(line)
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	flush()
+
+	if !started {
+		return nil, errors.New("invalid ZAP export: no messages found")
+	}
+	return targets, nil
+}
+
+// zapTarget splits a message into its request and response. The response starts at the
+// first status line after the request headers, since request bodies are written as is.
+func zapTarget(message []byte) (model.Target, error) {
+	message = bytes.TrimLeft(message, "\r\n")
+	requestLine, _, _ := bytes.Cut(message, []byte("\n"))
+	fields := strings.Fields(string(requestLine))
+	if len(fields) < 2 {
+		return model.Target{}, errors.New("missing request line")
+	}
+	method, url := fields[0], fields[1]
+	if !strings.Contains(url, "://") {
+		return model.Target{}, fmt.Errorf("request line %q has no absolute URL", strings.TrimSpace(string(requestLine)))
+	}
+
+	headerEnd := bytes.Index(message, []byte("\n\r\n"))
+	if idx := bytes.Index(message, []byte("\n\n")); idx != -1 && (headerEnd == -1 || idx < headerEnd) {
+		headerEnd = idx
+	}
+	if headerEnd == -1 {
+		return model.Target{}, errors.New("message has no response")
+	}
+
+	start := -1
+	for offset := headerEnd; offset < len(message); {
+		if bytes.HasPrefix(message[offset+1:], []byte("HTTP/")) {
+			start = offset + 1
+			break
+		}
+		next := bytes.IndexByte(message[offset+1:], '\n')
+		if next == -1 {
+			break
+		}
+		offset += next + 1
+	}
+	if start == -1 {
+		return model.Target{}, errors.New("message has no response")
+	}
+
+	resp, err := readCapturedResponse(bytes.NewReader(message[start:]))
+	if err != nil {
+		return model.Target{}, fmt.Errorf("invalid response: %w", err)
+	}
+
+	return model.Target{
+		URL:        url,
+		Content:    string(resp.Body),
+		Prefetched: true,
+		Method:     method,
+		Status:     resp.Status,
+		MIMEType:   resp.MIMEType,
+	}, nil
+}
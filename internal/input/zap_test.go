@@ -0,0 +1,96 @@
+package input
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/config"
+)
+
+const zapExport = `==== 1 ==========
+GET https://example.com/app.js HTTP/1.1
+Host: example.com
+User-Agent: Mozilla/5.0
+
+HTTP/1.1 200 OK
+Content-Type: application/javascript
+Content-Length: 19
+
+fetch('/api/users')
+==== 2 ==========
+POST https://example.com/api/login HTTP/1.1
+Host: example.com
+Content-Type: application/x-www-form-urlencoded
+
+user=admin&pass=secret
+HTTP/1.1 302 Found
+Location: /dashboard
+Content-Type: text/html
+
+<a href="/dashboard">continue</a>
+==== 3 ==========
+garbage
+==== 4 ==========
+GET https://example.com/logo.png HTTP/1.1
+Host: example.com
+
+HTTP/1.1 200 OK
+Content-Type: image/png
+
+PNG
+`
+
+func TestResolveTargetsZAP(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "messages.txt")
+	writeFile(t, file, []byte(zapExport))
+
+	var warnings bytes.Buffer
+	oldOut := warningOut
+	warningOut = &warnings
+	t.Cleanup(func() {
+		warningOut = oldOut
+	})
+
+	targets, err := ResolveTargets(config.Config{Input: file, MIMETypes: []string{"javascript", "html"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(targets) != 2 {
+		t.Fatalf("expected 2 targets, got %+v", targets)
+	}
+
+	script := targets[0]
+	if script.URL != "https://example.com/app.js" || script.Content != "fetch('/api/users')" || !script.Prefetched {
+		t.Fatalf("unexpected script target %+v", script)
+	}
+	if script.Method != "GET" || script.Status != 200 || script.MIMEType != "application/javascript" {
+		t.Fatalf("unexpected script metadata %+v", script)
+	}
+
+	login := targets[1]
+	if login.URL != "https://example.com/api/login" || login.Method != "POST" || login.Status != 302 {
+		t.Fatalf("unexpected login target %+v", login)
+	}
+	if !strings.Contains(login.Content, `href="/dashboard"`) || strings.Contains(login.Content, "pass=secret") {
+		t.Fatalf("expected only the response body, got %q", login.Content)
+	}
+
+	if !strings.Contains(warnings.String(), "skipping ZAP message 3") {
+		t.Fatalf("expected a warning for the malformed message, got %q", warnings.String())
+	}
+}
+
+func TestResolveTargetsZAPFlag(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "targets.txt")
+	writeFile(t, file, []byte("https://example.com/app.js\n"))
+
+	if _, err := ResolveTargets(config.Config{Input: file, ZAP: true}); err == nil {
+		t.Fatalf("expected a target list in ZAP mode to fail")
+	}
+	targets, err := ResolveTargets(config.Config{Input: file})
+	if err != nil || len(targets) != 1 || targets[0].Prefetched {
+		t.Fatalf("expected a plain target list to be left alone, got %+v (%v)", targets, err)
+	}
+}
@@ -5,6 +5,13 @@ type Target struct {
 	URL        string
 	Content    string
 	Prefetched bool
+	// Method, Status and MIMEType describe the exchange a prefetched target was
+	// captured from.
+	Method   string
+	Status   int
+	MIMEType string
+	// CapturedAt is when an archived target was captured, in RFC 3339 format.
+	CapturedAt string
 }
 
 // Endpoint represents an extracted endpoint and its context.
@@ -13,3 +20,56 @@ type Endpoint struct {
 	Context string
 	Line    int
 }
+
+// Redirect describes a single hop of an HTTP redirect chain.
+type Redirect struct {
+	From   string
+	To     string
+	Status int
+}
+
+// Request describes a network request observed while rendering a page.
+type Request struct {
+	Method   string
+	URL      string
+	Type     string
+	Headers  map[string]string `json:",omitempty"`
+	PostData string            `json:",omitempty"`
+}
+
+// Cookie is a browser cookie carried over from an authenticated session.
+type Cookie struct {
+	Name     string
+	Value    string
+	Domain   string
+	Path     string
+	Secure   bool
+	HTTPOnly bool
+}
+
+// Session holds the state captured after logging in, shared by every fetcher.
+type Session struct {
+	// Origin is the origin whose storage was captured (e.g. "https://app.example.com").
+	Origin         string
+	Cookies        []Cookie
+	LocalStorage   map[string]string
+	SessionStorage map[string]string
+}
+
+// Route is a client-side route or lazily loaded module declared by an SPA framework.
+type Route struct {
+	Framework string
+	// Kind is "route" for navigable paths and "module" for lazily loaded code.
+	Kind string
+	Path string
+	Line int
+}
+
+// StateEntry is a piece of runtime state captured from a rendered page, such as a
+// window global or a storage item.
+type StateEntry struct {
+	// Source is "window", "localStorage" or "sessionStorage".
+	Source string
+	Name   string
+	Value  string
+}
@@ -5,10 +5,11 @@ import (
 	"compress/gzip"
 	"compress/zlib"
 	"context"
-	"crypto/tls"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"net/http/cookiejar"
 	"net/url"
 	"os"
 	"strings"
@@ -18,12 +19,41 @@ import (
 	"github.com/andybalholm/brotli"
 	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/browser"
 	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/config"
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/model"
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/proxy"
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/resolve"
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/tlsconfig"
 )
 
 type clientSettings struct {
-	proxy    string
-	insecure bool
-	timeout  time.Duration
+	proxy                  string
+	proxyList              string
+	proxyRotation          config.ProxyRotation
+	insecure               bool
+	clientCert             string
+	clientKey              string
+	clientCertPassword     string
+	caCert                 string
+	resolve                string
+	dnsServer              string
+	timeout                time.Duration
+	redirects              config.RedirectPolicy
+	maxRedirects           int
+	scope                  string
+	scopeIncludeSubdomains bool
+	session                *model.Session
+}
+
+// Result holds the content retrieved for a resource along with transport metadata.
+type Result struct {
+	Content   string
+	Redirects []model.Redirect
+	// Status is the status code of the final HTTP response. It is zero for rendered pages.
+	Status int
+	// Requests, Scripts and State are only populated when the resource was rendered.
+	Requests []model.Request
+	Scripts  []model.Target
+	State    []model.StateEntry
 }
 
 var (
@@ -34,9 +64,22 @@ var (
 
 func getHTTPClient(cfg config.Config) (*http.Client, error) {
 	desired := clientSettings{
-		proxy:    cfg.Proxy,
-		insecure: cfg.Insecure,
-		timeout:  cfg.Timeout,
+		proxy:                  cfg.Proxy,
+		proxyList:              cfg.ProxyList,
+		proxyRotation:          cfg.ProxyRotation,
+		insecure:               cfg.Insecure,
+		clientCert:             cfg.ClientCert,
+		clientKey:              cfg.ClientKey,
+		clientCertPassword:     cfg.ClientCertPassword,
+		caCert:                 cfg.CACert,
+		resolve:                fmt.Sprint(cfg.Resolve),
+		dnsServer:              cfg.DNSServer,
+		timeout:                cfg.Timeout,
+		redirects:              cfg.Redirects,
+		maxRedirects:           cfg.MaxRedirects,
+		scope:                  cfg.Scope,
+		scopeIncludeSubdomains: cfg.ScopeIncludeSubdomains,
+		session:                cfg.Session,
 	}
 
 	clientMu.Lock()
@@ -52,14 +95,49 @@ func getHTTPClient(cfg config.Config) (*http.Client, error) {
 	}
 
 	sharedClient = &http.Client{
-		Timeout:   cfg.Timeout,
-		Transport: transport,
+		Timeout:       cfg.Timeout,
+		Transport:     transport,
+		CheckRedirect: redirectPolicy(cfg),
+	}
+	if cfg.Session != nil {
+		sharedClient.Jar = sessionJar(cfg.Session)
 	}
 	sharedSetting = desired
 
 	return sharedClient, nil
 }
 
+// redirectPolicy builds the CheckRedirect hook for the configured policy. Redirects
+// that are not followed stop the chain and the redirect response itself is returned.
+func redirectPolicy(cfg config.Config) func(*http.Request, []*http.Request) error {
+	limit := cfg.MaxRedirects
+	if limit <= 0 {
+		limit = config.DefaultMaxRedirects
+	}
+
+	return func(req *http.Request, via []*http.Request) error {
+		switch cfg.Redirects {
+		case config.RedirectNone:
+			return http.ErrUseLastResponse
+		case config.RedirectScope:
+			scope, includeSubdomains := cfg.Scope, cfg.ScopeIncludeSubdomains
+			if scope == "" && len(via) > 0 {
+				// Without an explicit scope, stay on the host that was originally requested.
+				scope, includeSubdomains = via[0].URL.Hostname(), false
+			}
+			if !WithinScope(req.URL.String(), scope, includeSubdomains) {
+				return http.ErrUseLastResponse
+			}
+		}
+
+		if len(via) > limit {
+			return http.ErrUseLastResponse
+		}
+
+		return nil
+	}
+}
+
 func buildTransport(cfg config.Config) (*http.Transport, error) {
 	base, ok := http.DefaultTransport.(*http.Transport)
 	if !ok {
@@ -73,21 +151,29 @@ func buildTransport(cfg config.Config) (*http.Transport, error) {
 		transport.ResponseHeaderTimeout = cfg.Timeout
 	}
 
-	if cfg.Proxy != "" {
-		proxyURL, err := url.Parse(cfg.Proxy)
-		if err != nil {
-			return nil, err
+	pool, err := proxy.FromConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if pool != nil {
+		transport.Proxy = func(req *http.Request) (*url.URL, error) {
+			if selected, ok := req.Context().Value(proxyContextKey{}).(*url.URL); ok {
+				return selected, nil
+			}
+			return pool.Next(), nil
 		}
-		transport.Proxy = http.ProxyURL(proxyURL)
 	}
 
-	if cfg.Insecure {
-		if transport.TLSClientConfig == nil {
-			transport.TLSClientConfig = &tls.Config{}
-		} else {
-			transport.TLSClientConfig = transport.TLSClientConfig.Clone()
-		}
-		transport.TLSClientConfig.InsecureSkipVerify = true
+	if dial := resolve.DialContext(cfg); dial != nil {
+		transport.DialContext = dial
+	}
+
+	tlsCfg, err := tlsconfig.Build(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if tlsCfg != nil {
+		transport.TLSClientConfig = tlsCfg
 	}
 
 	return transport, nil
@@ -95,19 +181,29 @@ func buildTransport(cfg config.Config) (*http.Transport, error) {
 
 // Fetch retrieves the content for the provided URL.
 func Fetch(ctx context.Context, rawURL string, cfg config.Config) (string, error) {
+	result, err := FetchResource(ctx, rawURL, cfg)
+	if err != nil {
+		return "", err
+	}
+	return result.Content, nil
+}
+
+// FetchResource retrieves the provided URL and reports the content together with
+// the redirect chain, or the runtime activity when rendering, observed while fetching it.
+func FetchResource(ctx context.Context, rawURL string, cfg config.Config) (Result, error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
 
 	if cfg.Render {
-		rendered, renderErr := browser.FetchRendered(ctx, rawURL, cfg)
+		page, renderErr := browser.FetchRendered(ctx, rawURL, cfg)
 		if renderErr == nil {
-			return rendered, nil
+			return Result{Content: page.HTML, Requests: page.Requests, Scripts: page.Scripts, State: page.State}, nil
 		}
 
 		plain, plainErr := fetchWithHTTP(ctx, rawURL, cfg)
 		if plainErr != nil {
-			return "", errors.Join(renderErr, plainErr)
+			return Result{}, errors.Join(renderErr, plainErr)
 		}
 
 		return plain, nil
@@ -116,45 +212,111 @@ func Fetch(ctx context.Context, rawURL string, cfg config.Config) (string, error
 	return fetchWithHTTP(ctx, rawURL, cfg)
 }
 
-func fetchWithHTTP(ctx context.Context, rawURL string, cfg config.Config) (string, error) {
+// proxyContextKey carries the proxy selected for a request so that failures can be
+// attributed to it.
+type proxyContextKey struct{}
+
+func fetchWithHTTP(ctx context.Context, rawURL string, cfg config.Config) (Result, error) {
 	client, err := getHTTPClient(cfg)
 	if err != nil {
-		return "", err
+		return Result{}, err
 	}
-	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+
+	pool, err := proxy.FromConfig(cfg)
 	if err != nil {
-		return "", err
+		return Result{}, err
 	}
 
-	req = req.WithContext(ctx)
+	// Retry through the remaining proxies when the selected one is unreachable.
+	attempts := 1
+	if pool.Len() > 1 {
+		attempts = pool.Len()
+	}
 
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/58.0.3029.110 Safari/537.36")
-	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/webp,*/*;q=0.8")
-	req.Header.Set("Accept-Language", "en-US,en;q=0.8")
-	req.Header.Set("Accept-Encoding", "gzip, deflate, br")
-	if cfg.Cookies != "" {
-		req.Header.Set("Cookie", cfg.Cookies)
+	for attempt := 1; ; attempt++ {
+		reqCtx := ctx
+		selected := pool.Next()
+		if selected != nil {
+			reqCtx = context.WithValue(ctx, proxyContextKey{}, selected)
+		}
+
+		result, err := doRequest(reqCtx, client, rawURL, cfg)
+		if err == nil || selected == nil || !proxy.IsProxyError(err) {
+			return result, err
+		}
+
+		pool.MarkFailed(selected)
+		if attempt >= attempts || ctx.Err() != nil {
+			return result, err
+		}
+	}
+}
+
+func doRequest(ctx context.Context, client *http.Client, rawURL string, cfg config.Config) (Result, error) {
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return Result{}, err
 	}
 
-	for _, header := range cfg.Headers {
-		if header.Name == "" {
+	req = req.WithContext(ctx)
+
+	for _, header := range cfg.RequestHeaders() {
+		if header.Name == "Host" {
+			// net/http ignores Host entries in the header map.
+			req.Host = header.Value
 			continue
 		}
 		req.Header.Set(header.Name, header.Value)
 	}
+	if client.Jar != nil {
+		mergeSessionCookies(req, client.Jar)
+	}
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return "", err
+		return Result{}, err
 	}
 	defer resp.Body.Close()
 
 	data, err := readResponseBody(resp)
 	if err != nil {
-		return "", err
+		return Result{}, err
 	}
 
-	return string(data), nil
+	return Result{Content: string(data), Redirects: redirectChain(resp), Status: resp.StatusCode}, nil
+}
+
+// redirectChain reconstructs the redirect hops that led to resp. When the final
+// response is itself a redirect that was not followed, it is reported as the last hop.
+func redirectChain(resp *http.Response) []model.Redirect {
+	var hops []model.Redirect
+	for req := resp.Request; req != nil && req.Response != nil; req = req.Response.Request {
+		previous := req.Response.Request
+		if previous == nil {
+			break
+		}
+		hops = append(hops, model.Redirect{
+			From:   previous.URL.String(),
+			To:     req.URL.String(),
+			Status: req.Response.StatusCode,
+		})
+	}
+
+	for i, j := 0, len(hops)-1; i < j; i, j = i+1, j-1 {
+		hops[i], hops[j] = hops[j], hops[i]
+	}
+
+	if resp.Request != nil && resp.StatusCode >= 300 && resp.StatusCode < 400 {
+		if location, err := resp.Location(); err == nil {
+			hops = append(hops, model.Redirect{
+				From:   resp.Request.URL.String(),
+				To:     location.String(),
+				Status: resp.StatusCode,
+			})
+		}
+	}
+
+	return hops
 }
 
 // resetHTTPClient clears the shared HTTP client. It is intended for use in tests.
@@ -299,3 +461,73 @@ func IsDNSOrNetworkError(err error) bool {
 		strings.Contains(msg, "name resolution failed") ||
 		strings.Contains(msg, "temporary failure in name resolution")
 }
+
+// mergeSessionCookies drops the --cookies entries that the login session also holds
+// for the request URL. The jar adds its own cookies when the request is sent, so each
+// cookie is sent once and the session value wins.
+func mergeSessionCookies(req *http.Request, jar http.CookieJar) {
+	raw := req.Header.Get("Cookie")
+	if raw == "" {
+		return
+	}
+
+	session := make(map[string]struct{})
+	for _, cookie := range jar.Cookies(req.URL) {
+		session[cookie.Name] = struct{}{}
+	}
+	if len(session) == 0 {
+		return
+	}
+
+	var kept []string
+	for _, part := range strings.Split(raw, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, _, _ := strings.Cut(part, "=")
+		if _, ok := session[strings.TrimSpace(name)]; ok {
+			continue
+		}
+		kept = append(kept, part)
+	}
+
+	if len(kept) == 0 {
+		req.Header.Del("Cookie")
+		return
+	}
+	req.Header.Set("Cookie", strings.Join(kept, "; "))
+}
+
+// sessionJar returns a cookie jar holding the cookies captured at login, so each one
+// is only sent to the hosts and paths it was issued for.
+func sessionJar(session *model.Session) http.CookieJar {
+	jar, _ := cookiejar.New(nil)
+
+	for _, c := range session.Cookies {
+		host := strings.TrimPrefix(c.Domain, ".")
+		if host == "" {
+			continue
+		}
+
+		cookie := &http.Cookie{
+			Name:     c.Name,
+			Value:    c.Value,
+			Path:     c.Path,
+			Secure:   c.Secure,
+			HttpOnly: c.HTTPOnly,
+		}
+		// Chromium marks domain cookies with a leading dot; the rest are host-only.
+		if strings.HasPrefix(c.Domain, ".") {
+			cookie.Domain = host
+		}
+
+		scheme := "http"
+		if c.Secure {
+			scheme = "https"
+		}
+		jar.SetCookies(&url.URL{Scheme: scheme, Host: host, Path: "/"}, []*http.Cookie{cookie})
+	}
+
+	return jar
+}
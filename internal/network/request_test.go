@@ -5,11 +5,21 @@ import (
 	"compress/gzip"
 	"compress/zlib"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"errors"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -18,6 +28,7 @@ import (
 
 	"github.com/andybalholm/brotli"
 	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/config"
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/model"
 )
 
 func TestFetchDeflate(t *testing.T) {
@@ -131,6 +142,31 @@ func TestFetchAppliesCustomHeaders(t *testing.T) {
 	}
 }
 
+func TestFetchHostHeader(t *testing.T) {
+	resetHTTPClient()
+	t.Cleanup(resetHTTPClient)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Host != "virtual.example.com" {
+			t.Errorf("unexpected Host header: got %q", r.Host)
+		}
+		if got := r.Header.Get("User-Agent"); got != "Scanner/1.0" {
+			t.Errorf("unexpected User-Agent header: got %q", got)
+		}
+	}))
+	defer server.Close()
+
+	cfg := config.Config{
+		Timeout:   time.Second,
+		UserAgent: "Scanner/1.0",
+		Headers:   []config.Header{{Name: "Host", Value: "virtual.example.com"}},
+	}
+
+	if _, err := Fetch(context.Background(), server.URL, cfg); err != nil {
+		t.Fatalf("Fetch returned error: %v", err)
+	}
+}
+
 func TestFetchHandlesCorruptGzip(t *testing.T) {
 	resetHTTPClient()
 	t.Cleanup(resetHTTPClient)
@@ -225,6 +261,251 @@ func TestFetchConcurrentRequests(t *testing.T) {
 	}
 }
 
+func TestFetchResourceRecordsRedirectChain(t *testing.T) {
+	resetHTTPClient()
+	t.Cleanup(resetHTTPClient)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/start.js", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/middle.js", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/middle.js", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/final.js", http.StatusFound)
+	})
+	mux.HandleFunc("/final.js", func(w http.ResponseWriter, r *http.Request) {
+		if _, err := w.Write([]byte("final")); err != nil {
+			t.Fatalf("failed to write response: %v", err)
+		}
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	cfg := config.Config{Timeout: time.Second}
+	result, err := FetchResource(context.Background(), server.URL+"/start.js", cfg)
+	if err != nil {
+		t.Fatalf("FetchResource returned error: %v", err)
+	}
+
+	if result.Content != "final" {
+		t.Fatalf("unexpected content: %q", result.Content)
+	}
+
+	if len(result.Redirects) != 2 {
+		t.Fatalf("expected 2 redirect hops, got %#v", result.Redirects)
+	}
+
+	first, second := result.Redirects[0], result.Redirects[1]
+	if first.From != server.URL+"/start.js" || first.To != server.URL+"/middle.js" || first.Status != http.StatusMovedPermanently {
+		t.Fatalf("unexpected first hop: %#v", first)
+	}
+	if second.From != server.URL+"/middle.js" || second.To != server.URL+"/final.js" || second.Status != http.StatusFound {
+		t.Fatalf("unexpected second hop: %#v", second)
+	}
+}
+
+func TestFetchResourceRedirectPolicies(t *testing.T) {
+	offScope := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := w.Write([]byte("off-scope")); err != nil {
+			t.Fatalf("failed to write response: %v", err)
+		}
+	}))
+	defer offScope.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, offScope.URL+"/elsewhere.js", http.StatusFound)
+	}))
+	defer server.Close()
+
+	// Both test servers listen on 127.0.0.1, so reach the off-scope one by name.
+	offScope.URL = strings.Replace(offScope.URL, "127.0.0.1", "localhost", 1)
+
+	cases := []struct {
+		name     string
+		policy   config.RedirectPolicy
+		followed bool
+	}{
+		{name: "follow", policy: config.RedirectFollow, followed: true},
+		{name: "none", policy: config.RedirectNone, followed: false},
+		{name: "scope", policy: config.RedirectScope, followed: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resetHTTPClient()
+			t.Cleanup(resetHTTPClient)
+
+			cfg := config.Config{Timeout: time.Second, Redirects: tc.policy}
+			result, err := FetchResource(context.Background(), server.URL+"/app.js", cfg)
+			if err != nil {
+				t.Fatalf("FetchResource returned error: %v", err)
+			}
+
+			if got := result.Content == "off-scope"; got != tc.followed {
+				t.Fatalf("followed = %v, want %v (content %q)", got, tc.followed, result.Content)
+			}
+
+			if len(result.Redirects) != 1 {
+				t.Fatalf("expected the redirect hop to be recorded, got %#v", result.Redirects)
+			}
+			if result.Redirects[0].To != offScope.URL+"/elsewhere.js" {
+				t.Fatalf("unexpected redirect target: %q", result.Redirects[0].To)
+			}
+		})
+	}
+}
+
+func TestFetchResourceMaxRedirects(t *testing.T) {
+	resetHTTPClient()
+	t.Cleanup(resetHTTPClient)
+
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&hits, 1)
+		http.Redirect(w, r, "/hop"+strconv.Itoa(int(n))+".js", http.StatusFound)
+	}))
+	defer server.Close()
+
+	cfg := config.Config{Timeout: time.Second, MaxRedirects: 2}
+	result, err := FetchResource(context.Background(), server.URL+"/app.js", cfg)
+	if err != nil {
+		t.Fatalf("FetchResource returned error: %v", err)
+	}
+
+	if got := atomic.LoadInt32(&hits); got != 3 {
+		t.Fatalf("expected 3 requests (2 followed redirects), got %d", got)
+	}
+	if len(result.Redirects) != 3 {
+		t.Fatalf("expected 3 recorded hops, got %#v", result.Redirects)
+	}
+}
+
+func TestFetchRotatesProxyList(t *testing.T) {
+	resetHTTPClient()
+	t.Cleanup(resetHTTPClient)
+
+	target := "http://target.invalid/app.js"
+
+	var hitsA, hitsB int32
+	newProxy := func(hits *int32) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.String() != target {
+				t.Errorf("proxy received unexpected request URI %q", r.URL.String())
+			}
+			atomic.AddInt32(hits, 1)
+			if _, err := w.Write([]byte("proxied")); err != nil {
+				t.Fatalf("failed to write response: %v", err)
+			}
+		}))
+	}
+	proxyA := newProxy(&hitsA)
+	defer proxyA.Close()
+	proxyB := newProxy(&hitsB)
+	defer proxyB.Close()
+
+	// A proxy that refuses connections should be skipped in favour of a healthy one.
+	dead := httptest.NewServer(http.NotFoundHandler())
+	deadURL := dead.URL
+	dead.Close()
+
+	listPath := filepath.Join(t.TempDir(), "proxies.txt")
+	list := strings.Join([]string{proxyA.URL, deadURL, proxyB.URL}, "\n")
+	if err := os.WriteFile(listPath, []byte(list), 0o644); err != nil {
+		t.Fatalf("write proxy list: %v", err)
+	}
+
+	cfg := config.Config{Timeout: time.Second, ProxyList: listPath}
+	for i := 0; i < 4; i++ {
+		content, err := Fetch(context.Background(), target, cfg)
+		if err != nil {
+			t.Fatalf("Fetch returned error: %v", err)
+		}
+		if content != "proxied" {
+			t.Fatalf("unexpected content: %q", content)
+		}
+	}
+
+	if atomic.LoadInt32(&hitsA) != 2 || atomic.LoadInt32(&hitsB) != 2 {
+		t.Fatalf("expected requests to be balanced across healthy proxies, got A=%d B=%d", hitsA, hitsB)
+	}
+}
+
+func TestFetchMutualTLS(t *testing.T) {
+	resetHTTPClient()
+	t.Cleanup(resetHTTPClient)
+
+	dir := t.TempDir()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "client"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	clientCert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("parse certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+
+	certPath := filepath.Join(dir, "client.crt")
+	keyPath := filepath.Join(dir, "client.key")
+	if err := os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("write client certificate: %v", err)
+	}
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatalf("write client key: %v", err)
+	}
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) == 0 {
+			t.Errorf("expected a client certificate")
+		}
+		if _, err := w.Write([]byte("authenticated")); err != nil {
+			t.Fatalf("failed to write response: %v", err)
+		}
+	}))
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+
+	caPath := filepath.Join(dir, "ca.pem")
+	if err := os.WriteFile(caPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0o644); err != nil {
+		t.Fatalf("write CA bundle: %v", err)
+	}
+
+	cfg := config.Config{Timeout: time.Second, CACert: caPath}
+	if _, err := Fetch(context.Background(), server.URL, cfg); err == nil {
+		t.Fatal("expected handshake to fail without a client certificate")
+	}
+
+	cfg.ClientCert = certPath
+	cfg.ClientKey = keyPath
+	content, err := Fetch(context.Background(), server.URL, cfg)
+	if err != nil {
+		t.Fatalf("Fetch returned error: %v", err)
+	}
+	if content != "authenticated" {
+		t.Fatalf("unexpected content: %q", content)
+	}
+}
+
 func TestBuildTransportRespectsTimeout(t *testing.T) {
 	cfg := config.Config{Timeout: 30 * time.Second}
 
@@ -299,3 +580,68 @@ type mockTimeoutError struct{}
 func (mockTimeoutError) Error() string   { return "timeout" }
 func (mockTimeoutError) Timeout() bool   { return true }
 func (mockTimeoutError) Temporary() bool { return false }
+
+func TestFetchSendsLoginSessionCookies(t *testing.T) {
+	resetHTTPClient()
+	t.Cleanup(resetHTTPClient)
+
+	var received atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received.Store(r.Header.Get("Cookie"))
+		if _, err := w.Write([]byte("ok")); err != nil {
+			t.Fatalf("failed to write response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	host := strings.Split(strings.TrimPrefix(server.URL, "http://"), ":")[0]
+	cfg := config.Config{
+		Timeout: 5 * time.Second,
+		Session: &model.Session{Cookies: []model.Cookie{
+			{Name: "session", Value: "abc", Domain: host, Path: "/"},
+			{Name: "admin", Value: "yes", Domain: host, Path: "/admin"},
+			{Name: "other", Value: "nope", Domain: ".example.com", Path: "/"},
+		}},
+	}
+
+	if _, err := Fetch(context.Background(), server.URL+"/app.js", cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cookie, _ := received.Load().(string)
+	if cookie != "session=abc" {
+		t.Fatalf("expected only the matching session cookie, got %q", cookie)
+	}
+}
+
+func TestFetchMergesCookiesWithLoginSession(t *testing.T) {
+	resetHTTPClient()
+	t.Cleanup(resetHTTPClient)
+
+	var received atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received.Store(r.Header.Values("Cookie"))
+		if _, err := w.Write([]byte("ok")); err != nil {
+			t.Fatalf("failed to write response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	host := strings.Split(strings.TrimPrefix(server.URL, "http://"), ":")[0]
+	cfg := config.Config{
+		Timeout: 5 * time.Second,
+		Cookies: "session=stale; theme=dark",
+		Session: &model.Session{Cookies: []model.Cookie{
+			{Name: "session", Value: "abc", Domain: host, Path: "/"},
+		}},
+	}
+
+	if _, err := Fetch(context.Background(), server.URL+"/app.js", cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	values, _ := received.Load().([]string)
+	if got := strings.Join(values, "; "); got != "theme=dark; session=abc" {
+		t.Fatalf("expected raw cookies merged with the session, got %q", got)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/parser"
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/scope"
 )
 
 // ResourceType represents the type of resource discovered
@@ -149,23 +150,49 @@ func ResolveURL(raw, base string, allowedTypes ...ResourceType) (string, Resourc
 		}
 	}
 
+	resolved, ok := resolveReference(candidate, base)
+	return resolved, resourceType, ok
+}
+
+// ResolveScriptURL resolves a URL that is known to load a script, such as a worker or a
+// dynamic import, whatever its extension.
+func ResolveScriptURL(raw, base string) (string, bool) {
+	candidate := strings.TrimSpace(raw)
+	if candidate == "" {
+		return "", false
+	}
+
+	resolved, ok := resolveReference(candidate, base)
+	if !ok {
+		return "", false
+	}
+
+	parsed, err := url.Parse(resolved)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return "", false
+	}
+	return resolved, true
+}
+
+// resolveReference resolves candidate against the directory of base.
+func resolveReference(candidate, base string) (string, bool) {
 	ref, err := url.Parse(candidate)
 	if err != nil {
-		return "", resourceType, false
+		return "", false
 	}
 
 	if ref.IsAbs() {
-		return ref.String(), resourceType, true
+		return ref.String(), true
 	}
 
 	if strings.HasPrefix(candidate, "//") {
 		resolved := "https:" + candidate
-		return resolved, resourceType, true
+		return resolved, true
 	}
 
 	baseURL, err := url.Parse(base)
 	if err != nil {
-		return "", resourceType, false
+		return "", false
 	}
 
 	if baseURL.Scheme == "" {
@@ -186,53 +213,15 @@ func ResolveURL(raw, base string, allowedTypes ...ResourceType) (string, Resourc
 
 	resolved := baseURL.ResolveReference(ref)
 	if resolved == nil || resolved.Scheme == "" {
-		return "", resourceType, false
+		return "", false
 	}
 
-	return resolved.String(), resourceType, true
+	return resolved.String(), true
 }
 
 // WithinScope reports whether the provided resource URL belongs to the supplied scope domain.
 // The scope can be provided with or without a scheme (e.g. "https://example.com" or "example.com").
 // When includeSubdomains is true, subdomains of the provided scope are also considered in-scope.
-func WithinScope(resource, scope string, includeSubdomains bool) bool {
-	if scope == "" {
-		return true
-	}
-
-	parsedResource, err := url.Parse(resource)
-	if err != nil {
-		return false
-	}
-
-	resourceHost := parsedResource.Hostname()
-	if resourceHost == "" {
-		return false
-	}
-
-	parsedScope, err := url.Parse(scope)
-	if err != nil || parsedScope.Hostname() == "" {
-		parsedScope, err = url.Parse("https://" + scope)
-		if err != nil {
-			return false
-		}
-	}
-
-	scopeHost := parsedScope.Hostname()
-	if scopeHost == "" {
-		return false
-	}
-
-	resourceHost = strings.ToLower(resourceHost)
-	scopeHost = strings.ToLower(scopeHost)
-
-	if !includeSubdomains {
-		return resourceHost == scopeHost
-	}
-
-	if resourceHost == scopeHost {
-		return true
-	}
-
-	return strings.HasSuffix(resourceHost, "."+scopeHost)
+func WithinScope(resource, scopeDomain string, includeSubdomains bool) bool {
+	return scope.Within(resource, scopeDomain, includeSubdomains)
 }
@@ -68,6 +68,49 @@ func TestCheckURL(t *testing.T) {
 	}
 }
 
+func TestResolveScriptURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		base    string
+		wantURL string
+		wantOK  bool
+	}{
+		{
+			name:    "extensionless worker",
+			raw:     "/workers/sync",
+			base:    "https://example.com/app/page.html",
+			wantURL: "https://example.com/workers/sync",
+			wantOK:  true,
+		},
+		{
+			name:    "relative module",
+			raw:     "./chunks/admin.mjs?v=2",
+			base:    "https://example.com/static/main.js",
+			wantURL: "https://example.com/static/chunks/admin.mjs?v=2",
+			wantOK:  true,
+		},
+		{
+			name:   "non http scheme",
+			raw:    "data:text/javascript,void 0",
+			base:   "https://example.com/",
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ResolveScriptURL(tt.raw, tt.base)
+			if ok != tt.wantOK {
+				t.Fatalf("expected ok=%v, got %v (%q)", tt.wantOK, ok, got)
+			}
+			if tt.wantOK && got != tt.wantURL {
+				t.Fatalf("expected url %q, got %q", tt.wantURL, got)
+			}
+		})
+	}
+}
+
 func TestWithinScope(t *testing.T) {
 	tests := []struct {
 		name     string
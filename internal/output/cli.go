@@ -8,6 +8,36 @@ import (
 // PrintCLI prints endpoints to stdout in CLI mode.
 func PrintCLI(report ResourceReport) {
 	fmt.Printf("Resource: %s\n", report.Resource)
+	if exchange := report.Exchange(); exchange != "" {
+		fmt.Printf("  Captured response: %s\n", exchange)
+	}
+	if report.CapturedAt != "" {
+		fmt.Printf("  Captured at: %s\n", report.CapturedAt)
+	}
+	if len(report.Redirects) > 0 {
+		fmt.Println("  Redirect chain:")
+		for _, hop := range report.Redirects {
+			fmt.Printf("    [%d] %s -> %s\n", hop.Status, hop.From, hop.To)
+		}
+	}
+	if len(report.Runtime) > 0 {
+		fmt.Printf("  Runtime requests observed: %d\n", len(report.Runtime))
+		for _, req := range report.Runtime {
+			fmt.Printf("    [%s] %s %s\n", req.Type, req.Method, req.URL)
+		}
+	}
+	if len(report.Routes) > 0 {
+		fmt.Printf("  Client-side routes: %d\n", len(report.Routes))
+		for _, route := range report.Routes {
+			fmt.Printf("    [%s] %s %s\n", route.Framework, route.Kind, route.Path)
+		}
+	}
+	if len(report.State) > 0 {
+		fmt.Printf("  Runtime state captured: %d\n", len(report.State))
+		for _, entry := range report.State {
+			fmt.Printf("    [%s] %s (%d bytes)\n", entry.Source, entry.Name, len(entry.Value))
+		}
+	}
 	fmt.Printf("  Endpoints discovered: %d\n", len(report.Endpoints))
 
 	if len(report.Endpoints) == 0 {
@@ -29,6 +59,9 @@ func PrintSummary(meta Metadata) {
 	fmt.Println("=======")
 	fmt.Printf("Resources scanned : %d\n", meta.TotalResources)
 	fmt.Printf("Endpoints discovered: %d\n", meta.TotalEndpoints)
+	if meta.SecretsVersion != "" {
+		fmt.Printf("Secrets ruleset   : %s\n", meta.SecretsVersion)
+	}
 }
 
 // PrintGFFindings prints GF pattern matching results to stdout.
@@ -31,6 +31,16 @@ func AppendHTML(builder *strings.Builder, report ResourceReport) {
 	builder.WriteString("\" target=\"_blank\" rel=\"nofollow noopener noreferrer\">")
 	builder.WriteString(escapedURL)
 	builder.WriteString("</a></h2>")
+	if exchange := report.Exchange(); exchange != "" {
+		builder.WriteString("\n                <span class=\"badge badge-exchange\">")
+		builder.WriteString(htmlstd.EscapeString(exchange))
+		builder.WriteString("</span>")
+	}
+	if report.CapturedAt != "" {
+		builder.WriteString("\n                <span class=\"badge badge-captured\">")
+		builder.WriteString(htmlstd.EscapeString(report.CapturedAt))
+		builder.WriteString("</span>")
+	}
 	builder.WriteString("\n                <span class=\"badge\">")
 	count := report.EndpointCount()
 	builder.WriteString(fmt.Sprintf("%d endpoint", count))
@@ -40,6 +50,67 @@ func AppendHTML(builder *strings.Builder, report ResourceReport) {
 	builder.WriteString("</span>")
 	builder.WriteString("\n            </header>")
 
+	if len(report.Redirects) > 0 {
+		builder.WriteString("\n            <ol class=\"redirect-chain\">")
+		for _, hop := range report.Redirects {
+			builder.WriteString("\n                <li><span class=\"redirect-status\">")
+			builder.WriteString(strconv.Itoa(hop.Status))
+			builder.WriteString("</span> ")
+			builder.WriteString(htmlstd.EscapeString(hop.From))
+			builder.WriteString(" &rarr; ")
+			builder.WriteString(htmlstd.EscapeString(hop.To))
+			builder.WriteString("</li>")
+		}
+		builder.WriteString("\n            </ol>")
+	}
+
+	if len(report.Runtime) > 0 {
+		builder.WriteString("\n            <ul class=\"runtime-requests\">")
+		for _, req := range report.Runtime {
+			builder.WriteString("\n                <li><span class=\"runtime-type\">")
+			builder.WriteString(htmlstd.EscapeString(req.Type))
+			builder.WriteString("</span> <span class=\"runtime-method\">")
+			builder.WriteString(htmlstd.EscapeString(req.Method))
+			builder.WriteString("</span> ")
+			builder.WriteString(htmlstd.EscapeString(req.URL))
+			if req.PostData != "" {
+				builder.WriteString("\n                    <pre class=\"runtime-body\"><code>")
+				builder.WriteString(htmlstd.EscapeString(req.PostData))
+				builder.WriteString("</code></pre>")
+			}
+			builder.WriteString("</li>")
+		}
+		builder.WriteString("\n            </ul>")
+	}
+
+	if len(report.Routes) > 0 {
+		builder.WriteString("\n            <ul class=\"route-list\">")
+		for _, route := range report.Routes {
+			builder.WriteString("\n                <li><span class=\"route-framework\">")
+			builder.WriteString(htmlstd.EscapeString(route.Framework))
+			builder.WriteString("</span> <span class=\"route-kind\">")
+			builder.WriteString(htmlstd.EscapeString(route.Kind))
+			builder.WriteString("</span> ")
+			builder.WriteString(htmlstd.EscapeString(route.Path))
+			builder.WriteString("</li>")
+		}
+		builder.WriteString("\n            </ul>")
+	}
+
+	if len(report.State) > 0 {
+		builder.WriteString("\n            <div class=\"state-list\">")
+		for _, entry := range report.State {
+			builder.WriteString("\n                <details><summary><span class=\"state-source\">")
+			builder.WriteString(htmlstd.EscapeString(entry.Source))
+			builder.WriteString("</span> ")
+			builder.WriteString(htmlstd.EscapeString(entry.Name))
+			builder.WriteString("</summary><pre class=\"state-value\"><code>")
+			builder.WriteString(htmlstd.EscapeString(entry.Value))
+			builder.WriteString("</code></pre></details>")
+		}
+		builder.WriteString("\n            </div>")
+	}
+
 	if len(report.Endpoints) == 0 {
 		builder.WriteString("\n            <p class=\"resource-empty\">No endpoints were found for this resource.</p>")
 		builder.WriteString("\n        </section>")
@@ -95,6 +166,7 @@ func SaveHTML(content, outputPath string, meta Metadata, gfRules []string, gfFin
 		TotalEndpoints int
 		HasResults     bool
 		GFRules        string
+		SecretsVersion string
 		GFTotal        int
 		GFFindings     []GFFinding
 		HasGFFindings  bool
@@ -105,6 +177,7 @@ func SaveHTML(content, outputPath string, meta Metadata, gfRules []string, gfFin
 		TotalEndpoints: meta.TotalEndpoints,
 		HasResults:     meta.TotalResources > 0,
 		GFRules:        joinRules(gfRules),
+		SecretsVersion: meta.SecretsVersion,
 		GFTotal:        len(gfFindings),
 		GFFindings:     gfFindings,
 		HasGFFindings:  len(gfFindings) > 0,
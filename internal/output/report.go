@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -13,8 +14,38 @@ import (
 
 // ResourceReport describes the endpoints discovered for a single resource.
 type ResourceReport struct {
-	Resource  string
-	Endpoints []model.Endpoint
+	Resource string
+	// Method, Status and MIMEType are set for resources imported from traffic captures.
+	Method   string `json:",omitempty"`
+	Status   int    `json:",omitempty"`
+	MIMEType string `json:",omitempty"`
+	// CapturedAt is set for resources read from web archives, in RFC 3339 format.
+	CapturedAt string `json:",omitempty"`
+	Endpoints  []model.Endpoint
+	Redirects  []model.Redirect `json:",omitempty"`
+	// Runtime lists the requests the page issued while being rendered.
+	Runtime []model.Request `json:",omitempty"`
+	// Routes lists client-side routes and lazily loaded modules declared by SPA frameworks.
+	Routes []model.Route `json:",omitempty"`
+	// State holds the window globals and web storage captured from a rendered page.
+	State []model.StateEntry `json:",omitempty"`
+}
+
+// Exchange describes the captured request, response status and MIME type, e.g.
+// "GET 200 application/javascript". It is empty for resources that were not imported
+// from a capture.
+func (r ResourceReport) Exchange() string {
+	var parts []string
+	if r.Method != "" {
+		parts = append(parts, r.Method)
+	}
+	if r.Status != 0 {
+		parts = append(parts, strconv.Itoa(r.Status))
+	}
+	if r.MIMEType != "" {
+		parts = append(parts, r.MIMEType)
+	}
+	return strings.Join(parts, " ")
 }
 
 // EndpointCount returns the number of endpoints discovered for the resource.
@@ -27,6 +58,8 @@ type Metadata struct {
 	GeneratedAt    time.Time
 	TotalResources int
 	TotalEndpoints int
+	// SecretsVersion is the version of the built-in secrets ruleset, when it ran.
+	SecretsVersion string `json:",omitempty"`
 }
 
 // BuildMetadata creates a Metadata value from the provided reports.
@@ -61,6 +94,29 @@ func WriteRaw(path string, reports []ResourceReport, meta Metadata) error {
 		buf.WriteString(report.Resource)
 		buf.WriteByte('\n')
 
+		if exchange := report.Exchange(); exchange != "" {
+			buf.WriteString(fmt.Sprintf("#   Captured %s\n", exchange))
+		}
+		if report.CapturedAt != "" {
+			buf.WriteString(fmt.Sprintf("#   Captured at %s\n", report.CapturedAt))
+		}
+
+		for _, hop := range report.Redirects {
+			buf.WriteString(fmt.Sprintf("#   Redirect %d: %s -> %s\n", hop.Status, hop.From, hop.To))
+		}
+
+		for _, req := range report.Runtime {
+			buf.WriteString(fmt.Sprintf("#   Runtime %s %s (%s)\n", req.Method, req.URL, req.Type))
+		}
+
+		for _, route := range report.Routes {
+			buf.WriteString(fmt.Sprintf("#   Route [%s] %s %s\n", route.Framework, route.Kind, route.Path))
+		}
+
+		for _, entry := range report.State {
+			buf.WriteString(fmt.Sprintf("#   State %s.%s = %s\n", entry.Source, entry.Name, entry.Value))
+		}
+
 		if len(report.Endpoints) == 0 {
 			buf.WriteString("#   No endpoints were found.\n\n")
 			continue
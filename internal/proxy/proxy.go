@@ -0,0 +1,259 @@
+package proxy
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/config"
+)
+
+// defaultCooldown is how long a failing proxy is kept out of the rotation.
+const defaultCooldown = 30 * time.Second
+
+// Pool hands out proxies from a configured list, temporarily skipping the ones that failed.
+type Pool struct {
+	mu       sync.Mutex
+	entries  []*entry
+	next     int
+	rotation config.ProxyRotation
+	cooldown time.Duration
+	rng      *rand.Rand
+	now      func() time.Time
+}
+
+type entry struct {
+	url           *url.URL
+	disabledUntil time.Time
+}
+
+// NewPool creates a pool that rotates over the provided proxies.
+func NewPool(proxies []*url.URL, rotation config.ProxyRotation, cooldown time.Duration) *Pool {
+	if cooldown <= 0 {
+		cooldown = defaultCooldown
+	}
+
+	entries := make([]*entry, 0, len(proxies))
+	for _, u := range proxies {
+		if u == nil {
+			continue
+		}
+		entries = append(entries, &entry{url: u})
+	}
+
+	return &Pool{
+		entries:  entries,
+		rotation: rotation,
+		cooldown: cooldown,
+		rng:      rand.New(rand.NewSource(time.Now().UnixNano())),
+		now:      time.Now,
+	}
+}
+
+// Len returns the number of proxies managed by the pool.
+func (p *Pool) Len() int {
+	if p == nil {
+		return 0
+	}
+	return len(p.entries)
+}
+
+// Next returns the proxy to use for the next request, or nil when the pool is empty.
+// When every proxy is cooling down, the one that becomes available first is returned
+// so that requests keep flowing.
+func (p *Pool) Next() *url.URL {
+	if p == nil || len(p.entries) == 0 {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+
+	var healthy []int
+	for idx, e := range p.entries {
+		if !now.Before(e.disabledUntil) {
+			healthy = append(healthy, idx)
+		}
+	}
+
+	if len(healthy) == 0 {
+		soonest := p.entries[0]
+		for _, e := range p.entries[1:] {
+			if e.disabledUntil.Before(soonest.disabledUntil) {
+				soonest = e
+			}
+		}
+		return soonest.url
+	}
+
+	if p.rotation == config.ProxyRotationRandom {
+		return p.entries[healthy[p.rng.Intn(len(healthy))]].url
+	}
+
+	for i := 0; i < len(p.entries); i++ {
+		idx := (p.next + i) % len(p.entries)
+		if !now.Before(p.entries[idx].disabledUntil) {
+			p.next = idx + 1
+			return p.entries[idx].url
+		}
+	}
+
+	return p.entries[healthy[0]].url
+}
+
+// MarkFailed removes the proxy from the rotation for the pool cooldown period.
+func (p *Pool) MarkFailed(u *url.URL) {
+	if p == nil || u == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, e := range p.entries {
+		if e.url.String() == u.String() {
+			e.disabledUntil = p.now().Add(p.cooldown)
+			return
+		}
+	}
+}
+
+// Parse validates a proxy URL. Values without a scheme are treated as HTTP proxies.
+func Parse(raw string) (*url.URL, error) {
+	candidate := strings.TrimSpace(raw)
+	if candidate == "" {
+		return nil, errors.New("proxy URL cannot be empty")
+	}
+
+	if !strings.Contains(candidate, "://") {
+		candidate = "http://" + candidate
+	}
+
+	u, err := url.Parse(candidate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy %q: %w", raw, err)
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q (expected http, https, socks5 or socks5h)", u.Scheme)
+	}
+
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("proxy %q is missing a host", raw)
+	}
+
+	return u, nil
+}
+
+// Load reads a proxy list file containing one proxy URL per line. Blank lines and
+// lines starting with '#' are ignored.
+func Load(path string) ([]*url.URL, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var proxies []*url.URL
+	scanner := bufio.NewScanner(file)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		u, err := Parse(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, lineNo, err)
+		}
+		proxies = append(proxies, u)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(proxies) == 0 {
+		return nil, fmt.Errorf("proxy list %s does not contain any proxies", path)
+	}
+
+	return proxies, nil
+}
+
+type poolSettings struct {
+	proxy    string
+	list     string
+	rotation config.ProxyRotation
+}
+
+var (
+	poolMu       sync.Mutex
+	sharedPool   *Pool
+	sharedConfig poolSettings
+)
+
+// FromConfig returns the proxy pool described by cfg. The pool is shared between
+// callers using the same proxy settings so health information survives across
+// requests. A nil pool is returned when no proxy is configured.
+func FromConfig(cfg config.Config) (*Pool, error) {
+	desired := poolSettings{proxy: cfg.Proxy, list: cfg.ProxyList, rotation: cfg.ProxyRotation}
+	if desired.proxy == "" && desired.list == "" {
+		return nil, nil
+	}
+
+	poolMu.Lock()
+	defer poolMu.Unlock()
+
+	if sharedPool != nil && sharedConfig == desired {
+		return sharedPool, nil
+	}
+
+	var proxies []*url.URL
+	if desired.proxy != "" {
+		u, err := Parse(desired.proxy)
+		if err != nil {
+			return nil, err
+		}
+		proxies = append(proxies, u)
+	}
+
+	if desired.list != "" {
+		loaded, err := Load(desired.list)
+		if err != nil {
+			return nil, err
+		}
+		proxies = append(proxies, loaded...)
+	}
+
+	sharedPool = NewPool(proxies, desired.rotation, defaultCooldown)
+	sharedConfig = desired
+
+	return sharedPool, nil
+}
+
+// IsProxyError reports whether err was caused by the proxy itself rather than the target.
+func IsProxyError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	msg := err.Error()
+	return strings.Contains(msg, "proxyconnect") ||
+		strings.Contains(msg, "socks connect") ||
+		strings.Contains(msg, "ERR_PROXY_CONNECTION_FAILED") ||
+		strings.Contains(msg, "ERR_SOCKS_CONNECTION_FAILED") ||
+		strings.Contains(msg, "ERR_TUNNEL_CONNECTION_FAILED")
+}
@@ -0,0 +1,50 @@
+package resolve
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/config"
+)
+
+// DialFunc matches the signature of http.Transport.DialContext.
+type DialFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// DialContext returns a dial function honouring the --resolve overrides and the
+// custom DNS server configured in cfg, or nil when the defaults apply. Only the
+// address that is dialled changes, so the Host header and TLS SNI keep the
+// original hostname.
+func DialContext(cfg config.Config) DialFunc {
+	if len(cfg.Resolve) == 0 && cfg.DNSServer == "" {
+		return nil
+	}
+
+	// Match the settings of http.DefaultTransport's dialer.
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+
+	if cfg.DNSServer != "" {
+		server := cfg.DNSServer
+		dialer.Resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, network, server)
+			},
+		}
+	}
+
+	overrides := make(map[string]string, len(cfg.Resolve))
+	for _, rule := range cfg.Resolve {
+		key := strings.ToLower(net.JoinHostPort(rule.Host, rule.Port))
+		overrides[key] = net.JoinHostPort(rule.Address, rule.Port)
+	}
+
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		if target, ok := overrides[strings.ToLower(address)]; ok {
+			address = target
+		}
+		return dialer.DialContext(ctx, network, address)
+	}
+}
@@ -0,0 +1,137 @@
+package resolve
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/config"
+)
+
+func TestDialContextDefaults(t *testing.T) {
+	t.Parallel()
+
+	if DialContext(config.Config{}) != nil {
+		t.Fatal("expected nil dialer when no overrides are configured")
+	}
+}
+
+func TestDialContextResolveOverride(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, r.Host)
+	}))
+	defer server.Close()
+
+	_, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("split listener address: %v", err)
+	}
+
+	cfg := config.Config{Resolve: []config.ResolveRule{{Host: "staging.example.invalid", Port: port, Address: "127.0.0.1"}}}
+	host := fetchHost(t, DialContext(cfg), "http://STAGING.example.invalid:"+port+"/")
+
+	if want := "STAGING.example.invalid:" + port; host != want {
+		t.Fatalf("expected Host header %q to be preserved, got %q", want, host)
+	}
+}
+
+func TestDialContextCustomDNSServer(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, r.Host)
+	}))
+	defer server.Close()
+
+	_, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("split listener address: %v", err)
+	}
+
+	dns := startDNSServer(t, net.IPv4(127, 0, 0, 1))
+
+	cfg := config.Config{DNSServer: dns}
+	host := fetchHost(t, DialContext(cfg), "http://internal.example.invalid:"+port+"/")
+
+	if want := "internal.example.invalid:" + port; host != want {
+		t.Fatalf("unexpected Host header %q", host)
+	}
+}
+
+func fetchHost(t *testing.T, dial DialFunc, rawURL string) string {
+	t.Helper()
+
+	client := &http.Client{
+		Timeout:   2 * time.Second,
+		Transport: &http.Transport{DialContext: dial},
+	}
+	resp, err := client.Get(rawURL)
+	if err != nil {
+		t.Fatalf("GET %s: %v", rawURL, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read body: %v", err)
+	}
+	return string(body)
+}
+
+// startDNSServer answers every A query with addr and every other query with an empty answer.
+func startDNSServer(t *testing.T, addr net.IP) string {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen udp: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, peer, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if n < 12 {
+				continue
+			}
+
+			// Walk the question name to find the query type.
+			end := 12
+			for end < n && buf[end] != 0 {
+				end += int(buf[end]) + 1
+			}
+			end++
+			if end+4 > n {
+				continue
+			}
+			qtype := binary.BigEndian.Uint16(buf[end : end+2])
+			question := buf[12 : end+4]
+
+			resp := make([]byte, 12, 64)
+			copy(resp, buf[:2])
+			resp[2], resp[3] = 0x81, 0x80 // standard response, recursion available
+			binary.BigEndian.PutUint16(resp[4:], 1)
+			resp = append(resp, question...)
+
+			if qtype == 1 {
+				binary.BigEndian.PutUint16(resp[6:], 1)
+				resp = append(resp, 0xc0, 0x0c, 0, 1, 0, 1, 0, 0, 0, 60, 0, 4)
+				resp = append(resp, addr.To4()...)
+			}
+
+			_, _ = conn.WriteTo(resp, peer)
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
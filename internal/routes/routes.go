@@ -0,0 +1,294 @@
+// Package routes recognises the client-side routes and lazily loaded modules that
+// single-page application frameworks declare in their bundles and pages.
+package routes
+
+import (
+	"encoding/json"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/imports"
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/model"
+)
+
+// Route kinds.
+const (
+	KindRoute  = "route"
+	KindModule = "module"
+)
+
+// Framework names reported for detected routes.
+const (
+	FrameworkReactRouter = "react-router"
+	FrameworkVueRouter   = "vue-router"
+	FrameworkAngular     = "angular"
+	FrameworkNext        = "next.js"
+	FrameworkNuxt        = "nuxt"
+	// FrameworkGeneric is used for route tables whose framework cannot be told apart.
+	FrameworkGeneric = "spa-router"
+)
+
+const (
+	// routeLookBehind and routeLookAhead bound the part of a route object inspected
+	// around its path property.
+	routeLookBehind = 160
+	routeLookAhead  = 320
+)
+
+var (
+	routeObjectPath = regexp.MustCompile("\\bpath\\s*:\\s*([\"'`])([^\"'`\\n]*)[\"'`]")
+	routeKey        = regexp.MustCompile(`\b(element|Component|component|lazy|loader|errorElement|loadChildren|loadComponent|redirectTo|pathMatch|canActivate|redirect|beforeEnter)\s*:`)
+	routePathValue  = regexp.MustCompile(`^(?:/|\*\*?|[\w\-:.*\[\]]+)[\w\-/:.*()?\[\]]*$`)
+	legacyChildren  = regexp.MustCompile(`\bloadChildren\s*:\s*["']([^"'#]+)(?:#\w+)?["']`)
+	jsxRoute        = regexp.MustCompile(`<Route\b[^>]*?\bpath=(?:\{\s*)?["']([^"']+)["']`)
+
+	nextManifestRoute = regexp.MustCompile(`["'](/[^"']*)["']\s*:\s*\[`)
+	nextSortedPages   = regexp.MustCompile(`sortedPages\s*:\s*\[((?:\s*["'][^"']*["']\s*,?)*)\]`)
+	nextChunk         = regexp.MustCompile(`["']((?:/_next/)?static/chunks/[^"']+\.js)["']`)
+	nextData          = regexp.MustCompile(`(?s)<script[^>]*\bid=["']__NEXT_DATA__["'][^>]*>(.*?)</script>`)
+	quotedString      = regexp.MustCompile(`["']([^"']+)["']`)
+
+	nuxtRoutePath = regexp.MustCompile(`\broutePath\s*:\s*["']([^"']+)["']`)
+	nuxtChunk     = regexp.MustCompile(`["']((?:[\w.:/-]*)?/_nuxt/[^"']+\.m?js)["']`)
+)
+
+var frameworkMarkers = []struct {
+	framework string
+	markers   []string
+}{
+	{FrameworkAngular, []string{"@angular/router", "RouterModule", "loadChildren", "loadComponent", "provideRouter"}},
+	{FrameworkReactRouter, []string{"react-router", "createBrowserRouter", "RouterProvider", "useNavigate", "<Route"}},
+	{FrameworkVueRouter, []string{"vue-router", "createWebHistory", "createWebHashHistory", "VueRouter", "$router"}},
+}
+
+// Find returns the routes and lazily loaded modules declared in content. Results are
+// deduplicated and ordered by their position in content.
+func Find(content string) []model.Route {
+	f := finder{content: content, seen: make(map[model.Route]struct{})}
+
+	f.routeTables()
+	f.jsxRoutes()
+	f.next()
+	f.nuxt()
+
+	sort.SliceStable(f.routes, func(i, j int) bool { return f.routes[i].Line < f.routes[j].Line })
+	return f.routes
+}
+
+// Modules returns the paths of the lazily loaded chunks among routes. Module specifiers
+// that the framework resolves at build time, such as Angular's legacy loadChildren
+// strings, name no file on the server and are left out.
+func Modules(routes []model.Route) []string {
+	var modules []string
+	for _, route := range routes {
+		if route.Kind != KindModule {
+			continue
+		}
+		switch strings.ToLower(path.Ext(route.Path)) {
+		case ".js", ".mjs", ".cjs":
+			modules = append(modules, route.Path)
+		}
+	}
+	return modules
+}
+
+type finder struct {
+	content  string
+	newlines []int
+	routes   []model.Route
+	seen     map[model.Route]struct{}
+}
+
+func (f *finder) add(framework, kind, path string, offset int) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return
+	}
+
+	key := model.Route{Framework: framework, Kind: kind, Path: path}
+	if _, ok := f.seen[key]; ok {
+		return
+	}
+	f.seen[key] = struct{}{}
+
+	key.Line = f.line(offset)
+	f.routes = append(f.routes, key)
+}
+
+func (f *finder) line(offset int) int {
+	if f.newlines == nil {
+		f.newlines = []int{}
+		for idx := 0; idx < len(f.content); idx++ {
+			if f.content[idx] == '\n' {
+				f.newlines = append(f.newlines, idx)
+			}
+		}
+	}
+	return sort.SearchInts(f.newlines, offset) + 1
+}
+
+// markedFramework returns the router framework whose markers appear in the content.
+func (f *finder) markedFramework() string {
+	for _, candidate := range frameworkMarkers {
+		for _, marker := range candidate.markers {
+			if strings.Contains(f.content, marker) {
+				return candidate.framework
+			}
+		}
+	}
+	return ""
+}
+
+// routeTables finds route configuration objects such as {path:"/admin",element:...}
+// shared by React Router, Vue Router and Angular, and classifies them by their keys.
+func (f *finder) routeTables() {
+	matches := routeObjectPath.FindAllStringSubmatchIndex(f.content, -1)
+	if len(matches) == 0 {
+		return
+	}
+	marked := f.markedFramework()
+
+	for i, m := range matches {
+		path := f.content[m[4]:m[5]]
+		if path != "" && !routePathValue.MatchString(path) {
+			continue
+		}
+
+		// The object starts at the closest brace before the path, without reaching
+		// back into the previous route.
+		start := max(m[0]-routeLookBehind, 0)
+		if i > 0 {
+			start = max(start, matches[i-1][1])
+		}
+		if brace := strings.LastIndexByte(f.content[start:m[0]], '{'); brace != -1 {
+			start += brace
+		}
+
+		end := min(m[1]+routeLookAhead, len(f.content))
+		if i+1 < len(matches) && matches[i+1][0] < end {
+			end = matches[i+1][0]
+		}
+		window := f.content[start:end]
+
+		keys := routeKey.FindAllStringSubmatch(window, -1)
+		if len(keys) == 0 {
+			continue
+		}
+
+		framework := classify(keys, marked)
+		if path == "" {
+			// Angular and Vue use an empty path for the default child route.
+			path = "/"
+		}
+		f.add(framework, KindRoute, path, m[0])
+
+		for _, imp := range imports.Find(window) {
+			if imp.Kind == imports.KindDynamicImport {
+				f.add(framework, KindModule, imp.URL, m[0])
+			}
+		}
+		for _, child := range legacyChildren.FindAllStringSubmatchIndex(window, -1) {
+			f.add(framework, KindModule, window[child[2]:child[3]], start+child[0])
+		}
+	}
+}
+
+func classify(keys [][]string, marked string) string {
+	var react, angular, vue bool
+	for _, key := range keys {
+		switch key[1] {
+		case "element", "Component", "lazy", "loader", "errorElement":
+			react = true
+		case "loadChildren", "loadComponent", "redirectTo", "pathMatch", "canActivate":
+			angular = true
+		case "redirect", "beforeEnter":
+			vue = true
+		}
+	}
+
+	switch {
+	case angular:
+		return FrameworkAngular
+	case react:
+		return FrameworkReactRouter
+	case vue:
+		return FrameworkVueRouter
+	case marked != "":
+		return marked
+	default:
+		return FrameworkGeneric
+	}
+}
+
+// jsxRoutes finds <Route path="..."> elements in unbundled React sources and pages.
+func (f *finder) jsxRoutes() {
+	for _, m := range jsxRoute.FindAllStringSubmatchIndex(f.content, -1) {
+		f.add(FrameworkReactRouter, KindRoute, f.content[m[2]:m[3]], m[0])
+	}
+}
+
+// next handles Next.js build manifests and the __NEXT_DATA__ page payload.
+func (f *finder) next() {
+	if strings.Contains(f.content, "__BUILD_MANIFEST") {
+		for _, m := range nextManifestRoute.FindAllStringSubmatchIndex(f.content, -1) {
+			path := f.content[m[2]:m[3]]
+			if !strings.HasPrefix(path, "/_") {
+				f.add(FrameworkNext, KindRoute, path, m[0])
+			}
+		}
+		for _, m := range nextSortedPages.FindAllStringSubmatchIndex(f.content, -1) {
+			list := f.content[m[2]:m[3]]
+			for _, page := range quotedString.FindAllStringSubmatchIndex(list, -1) {
+				path := list[page[2]:page[3]]
+				if strings.HasPrefix(path, "/") && !strings.HasPrefix(path, "/_") {
+					f.add(FrameworkNext, KindRoute, path, m[2]+page[0])
+				}
+			}
+		}
+		for _, m := range nextChunk.FindAllStringSubmatchIndex(f.content, -1) {
+			f.add(FrameworkNext, KindModule, nextAssetPath(f.content[m[2]:m[3]]), m[0])
+		}
+	}
+
+	for _, m := range nextData.FindAllStringSubmatchIndex(f.content, -1) {
+		var data struct {
+			Page        string `json:"page"`
+			BuildID     string `json:"buildId"`
+			AssetPrefix string `json:"assetPrefix"`
+		}
+		if err := json.Unmarshal([]byte(f.content[m[2]:m[3]]), &data); err != nil {
+			continue
+		}
+
+		f.add(FrameworkNext, KindRoute, data.Page, m[0])
+		if data.BuildID != "" {
+			// The build manifest lists every page and its chunks.
+			base := strings.TrimSuffix(data.AssetPrefix, "/") + "/_next/static/" + data.BuildID
+			f.add(FrameworkNext, KindModule, base+"/_buildManifest.js", m[0])
+			f.add(FrameworkNext, KindModule, base+"/_ssgManifest.js", m[0])
+		}
+	}
+}
+
+func nextAssetPath(chunk string) string {
+	if strings.HasPrefix(chunk, "/_next/") {
+		return chunk
+	}
+	return "/_next/" + chunk
+}
+
+// nuxt handles the window.__NUXT__ payload and the /_nuxt/ chunks it references.
+func (f *finder) nuxt() {
+	if !strings.Contains(f.content, "__NUXT__") && !strings.Contains(f.content, "__NUXT_DATA__") {
+		return
+	}
+
+	for _, m := range nuxtRoutePath.FindAllStringSubmatchIndex(f.content, -1) {
+		f.add(FrameworkNuxt, KindRoute, f.content[m[2]:m[3]], m[0])
+	}
+	for _, m := range nuxtChunk.FindAllStringSubmatchIndex(f.content, -1) {
+		f.add(FrameworkNuxt, KindModule, f.content[m[2]:m[3]], m[0])
+	}
+}
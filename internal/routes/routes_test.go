@@ -0,0 +1,128 @@
+package routes
+
+import (
+	"testing"
+
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/model"
+)
+
+func routeSet(routes []model.Route) map[string]string {
+	out := make(map[string]string, len(routes))
+	for _, route := range routes {
+		out[route.Kind+" "+route.Path] = route.Framework
+	}
+	return out
+}
+
+func expectRoutes(t *testing.T, got []model.Route, want map[string]string) {
+	t.Helper()
+	set := routeSet(got)
+	for key, framework := range want {
+		actual, ok := set[key]
+		if !ok {
+			t.Errorf("missing %s in %v", key, set)
+			continue
+		}
+		if actual != framework {
+			t.Errorf("%s: expected framework %s, got %s", key, framework, actual)
+		}
+	}
+}
+
+func TestFindReactRouter(t *testing.T) {
+	content := `const router=createBrowserRouter([{path:"/",element:e(Home)},{path:"/admin/users/:id",lazy:()=>import("./AdminUser-3f2a.js")},{path:"*",element:e(NotFound)}]);
+function App(){return <Routes><Route path="/settings" element={<Settings/>}/></Routes>}`
+
+	got := Find(content)
+	expectRoutes(t, got, map[string]string{
+		"route /":                    FrameworkReactRouter,
+		"route /admin/users/:id":     FrameworkReactRouter,
+		"module ./AdminUser-3f2a.js": FrameworkReactRouter,
+		"route *":                    FrameworkReactRouter,
+		"route /settings":            FrameworkReactRouter,
+	})
+}
+
+func TestFindVueRouter(t *testing.T) {
+	content := `import {createRouter,createWebHistory} from "vue-router";
+const routes=[{path:"/",name:"home",component:Home},{path:"/billing",component:()=>import("./views/Billing.vue")},{path:"/old",redirect:"/billing"}];`
+
+	got := Find(content)
+	expectRoutes(t, got, map[string]string{
+		"route /":                    FrameworkVueRouter,
+		"route /billing":             FrameworkVueRouter,
+		"module ./views/Billing.vue": FrameworkVueRouter,
+		"route /old":                 FrameworkVueRouter,
+	})
+}
+
+func TestFindAngularRoutes(t *testing.T) {
+	content := `RouterModule.forRoot([{path:"",component:HomeComponent},{path:"admin",loadChildren:()=>import("./admin/admin.module").then(m=>m.AdminModule)},{path:"legacy",loadChildren:"./legacy/legacy.module#LegacyModule"},{path:"**",redirectTo:""}])`
+
+	got := Find(content)
+	expectRoutes(t, got, map[string]string{
+		"route /":                       FrameworkAngular,
+		"route admin":                   FrameworkAngular,
+		"module ./admin/admin.module":   FrameworkAngular,
+		"module ./legacy/legacy.module": FrameworkAngular,
+		"route **":                      FrameworkAngular,
+	})
+}
+
+func TestModulesSkipsModuleSpecifiers(t *testing.T) {
+	content := `RouterModule.forRoot([{path:"admin",loadChildren:()=>import("./chunk-ADMIN.js").then(m=>m.AdminModule)},{path:"legacy",loadChildren:"./legacy/legacy.module#LegacyModule"}])`
+
+	modules := Modules(Find(content))
+	if len(modules) != 1 || modules[0] != "./chunk-ADMIN.js" {
+		t.Fatalf("expected only the resolved chunk to be followed, got %v", modules)
+	}
+}
+
+func TestFindIgnoresNonRoutePaths(t *testing.T) {
+	content := `const icon={path:"M0 0h24v24H0z",fill:"none"};const cfg={path:"/tmp/cache",size:10};`
+	if got := Find(content); len(got) != 0 {
+		t.Fatalf("expected no routes, got %+v", got)
+	}
+}
+
+func TestFindNextBuildManifest(t *testing.T) {
+	content := `self.__BUILD_MANIFEST=function(s,a){return{__rewrites:{afterFiles:[],beforeFiles:[],fallback:[]},"/":[s,"static/chunks/pages/index-2b1.js"],"/admin/[id]":["static/chunks/pages/admin/[id]-9c0.js"],"/_error":["static/chunks/pages/_error-1.js"],sortedPages:["/","/_app","/_error","/admin/[id]","/login"]}}("static/chunks/1-a.js");`
+
+	got := Find(content)
+	expectRoutes(t, got, map[string]string{
+		"route /":           FrameworkNext,
+		"route /admin/[id]": FrameworkNext,
+		"route /login":      FrameworkNext,
+		"module /_next/static/chunks/pages/index-2b1.js":      FrameworkNext,
+		"module /_next/static/chunks/pages/admin/[id]-9c0.js": FrameworkNext,
+	})
+	if _, ok := routeSet(got)["route /_error"]; ok {
+		t.Fatal("internal Next.js pages should not be reported as routes")
+	}
+}
+
+func TestFindNextData(t *testing.T) {
+	content := `<html><body><div id="__next"></div><script id="__NEXT_DATA__" type="application/json">{"props":{},"page":"/products/[slug]","query":{"slug":"x"},"buildId":"Xy12","assetPrefix":"https://cdn.example.com"}</script></body></html>`
+
+	got := Find(content)
+	expectRoutes(t, got, map[string]string{
+		"route /products/[slug]": FrameworkNext,
+		"module https://cdn.example.com/_next/static/Xy12/_buildManifest.js": FrameworkNext,
+	})
+}
+
+func TestFindNuxtPayload(t *testing.T) {
+	content := `<script>window.__NUXT__=(function(a){return {layout:"default",routePath:"/account/orders",config:{}}}(null))</script><link rel="modulepreload" href="/_nuxt/entry.4f1.js"><script src="/_nuxt/pages/account.9a2.js"></script>`
+
+	got := Find(content)
+	expectRoutes(t, got, map[string]string{
+		"route /account/orders":              FrameworkNuxt,
+		"module /_nuxt/entry.4f1.js":         FrameworkNuxt,
+		"module /_nuxt/pages/account.9a2.js": FrameworkNuxt,
+	})
+
+	modules := Modules(got)
+	if len(modules) != 2 {
+		t.Fatalf("expected 2 modules, got %v", modules)
+	}
+}
@@ -0,0 +1,52 @@
+// Package scope decides whether URLs belong to the domain a scan is restricted to.
+package scope
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Within reports whether the provided resource URL belongs to the supplied scope domain.
+// The scope can be provided with or without a scheme (e.g. "https://example.com" or "example.com").
+// When includeSubdomains is true, subdomains of the provided scope are also considered in-scope.
+func Within(resource, scope string, includeSubdomains bool) bool {
+	if scope == "" {
+		return true
+	}
+
+	parsedResource, err := url.Parse(resource)
+	if err != nil {
+		return false
+	}
+
+	resourceHost := parsedResource.Hostname()
+	if resourceHost == "" {
+		return false
+	}
+
+	parsedScope, err := url.Parse(scope)
+	if err != nil || parsedScope.Hostname() == "" {
+		parsedScope, err = url.Parse("https://" + scope)
+		if err != nil {
+			return false
+		}
+	}
+
+	scopeHost := parsedScope.Hostname()
+	if scopeHost == "" {
+		return false
+	}
+
+	resourceHost = strings.ToLower(resourceHost)
+	scopeHost = strings.ToLower(scopeHost)
+
+	if !includeSubdomains {
+		return resourceHost == scopeHost
+	}
+
+	if resourceHost == scopeHost {
+		return true
+	}
+
+	return strings.HasSuffix(resourceHost, "."+scopeHost)
+}
@@ -0,0 +1,115 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"software.sslmate.com/src/go-pkcs12"
+
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/config"
+)
+
+// Build returns the TLS client configuration described by cfg, or nil when the
+// Go defaults should be used.
+func Build(cfg config.Config) (*tls.Config, error) {
+	if !cfg.Insecure && !HasCustomMaterial(cfg) {
+		return nil, nil
+	}
+
+	tlsCfg := &tls.Config{InsecureSkipVerify: cfg.Insecure}
+
+	if cfg.ClientCert != "" {
+		cert, err := loadClientCertificate(cfg.ClientCert, cfg.ClientKey, cfg.ClientCertPassword)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load client certificate: %w", err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+
+	if cfg.CACert != "" {
+		pool, err := loadCABundle(cfg.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load CA bundle: %w", err)
+		}
+		tlsCfg.RootCAs = pool
+	}
+
+	return tlsCfg, nil
+}
+
+// HasCustomMaterial reports whether cfg supplies client certificates or CA bundles.
+func HasCustomMaterial(cfg config.Config) bool {
+	return cfg.ClientCert != "" || cfg.CACert != ""
+}
+
+func loadClientCertificate(certPath, keyPath, password string) (tls.Certificate, error) {
+	data, err := os.ReadFile(certPath)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	ext := strings.ToLower(filepath.Ext(certPath))
+	if ext == ".p12" || ext == ".pfx" || !isPEM(data) {
+		if keyPath != "" {
+			return tls.Certificate{}, errors.New("--client-key cannot be combined with a PKCS#12 certificate")
+		}
+		return decodePKCS12(data, password)
+	}
+
+	keyData := data
+	if keyPath != "" {
+		keyData, err = os.ReadFile(keyPath)
+		if err != nil {
+			return tls.Certificate{}, err
+		}
+	}
+
+	return tls.X509KeyPair(data, keyData)
+}
+
+func decodePKCS12(data []byte, password string) (tls.Certificate, error) {
+	key, leaf, chain, err := pkcs12.DecodeChain(data, password)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("invalid PKCS#12 bundle: %w", err)
+	}
+
+	cert := tls.Certificate{
+		Certificate: [][]byte{leaf.Raw},
+		PrivateKey:  key,
+		Leaf:        leaf,
+	}
+	for _, ca := range chain {
+		cert.Certificate = append(cert.Certificate, ca.Raw)
+	}
+
+	return cert, nil
+}
+
+func loadCABundle(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no PEM certificates found in %s", path)
+	}
+
+	return pool, nil
+}
+
+func isPEM(data []byte) bool {
+	block, _ := pem.Decode(data)
+	return block != nil
+}
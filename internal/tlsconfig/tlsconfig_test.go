@@ -0,0 +1,173 @@
+package tlsconfig
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"software.sslmate.com/src/go-pkcs12"
+
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/config"
+)
+
+func TestBuildDefaults(t *testing.T) {
+	t.Parallel()
+
+	tlsCfg, err := Build(config.Config{})
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+	if tlsCfg != nil {
+		t.Fatalf("expected nil config when no TLS options are set, got %#v", tlsCfg)
+	}
+
+	tlsCfg, err = Build(config.Config{Insecure: true})
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+	if tlsCfg == nil || !tlsCfg.InsecureSkipVerify {
+		t.Fatalf("expected insecure config, got %#v", tlsCfg)
+	}
+}
+
+func TestBuildPEMClientCertificate(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	cert, key := generateCertificate(t)
+
+	certPath := filepath.Join(dir, "client.crt")
+	keyPath := filepath.Join(dir, "client.key")
+	writePEM(t, certPath, "CERTIFICATE", cert.Raw)
+	writePEM(t, keyPath, "PRIVATE KEY", marshalKey(t, key))
+
+	tlsCfg, err := Build(config.Config{ClientCert: certPath, ClientKey: keyPath, CACert: certPath})
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+
+	if len(tlsCfg.Certificates) != 1 {
+		t.Fatalf("expected 1 client certificate, got %d", len(tlsCfg.Certificates))
+	}
+	if tlsCfg.RootCAs == nil {
+		t.Fatal("expected custom root CAs to be configured")
+	}
+
+	// A combined PEM file holding both the certificate and the key is also accepted.
+	combined := filepath.Join(dir, "combined.pem")
+	data := append(readFile(t, certPath), readFile(t, keyPath)...)
+	if err := os.WriteFile(combined, data, 0o600); err != nil {
+		t.Fatalf("write combined PEM: %v", err)
+	}
+	if _, err := Build(config.Config{ClientCert: combined}); err != nil {
+		t.Fatalf("Build with combined PEM returned error: %v", err)
+	}
+}
+
+func TestBuildPKCS12ClientCertificate(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	cert, key := generateCertificate(t)
+
+	encoded, err := pkcs12.Modern.Encode(key, cert, nil, "s3cret")
+	if err != nil {
+		t.Fatalf("encode PKCS#12: %v", err)
+	}
+
+	path := filepath.Join(dir, "client.p12")
+	if err := os.WriteFile(path, encoded, 0o600); err != nil {
+		t.Fatalf("write PKCS#12: %v", err)
+	}
+
+	tlsCfg, err := Build(config.Config{ClientCert: path, ClientCertPassword: "s3cret"})
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+	if len(tlsCfg.Certificates) != 1 || tlsCfg.Certificates[0].Leaf == nil {
+		t.Fatalf("expected decoded PKCS#12 certificate, got %#v", tlsCfg.Certificates)
+	}
+
+	if _, err := Build(config.Config{ClientCert: path, ClientCertPassword: "wrong"}); err == nil {
+		t.Fatal("expected error for wrong PKCS#12 password")
+	}
+}
+
+func TestBuildInvalidCABundle(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(path, []byte("not a certificate"), 0o644); err != nil {
+		t.Fatalf("write CA bundle: %v", err)
+	}
+
+	if _, err := Build(config.Config{CACert: path}); err == nil {
+		t.Fatal("expected error for CA bundle without certificates")
+	}
+}
+
+func generateCertificate(t *testing.T) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "golinkfinder-test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("parse certificate: %v", err)
+	}
+
+	return cert, key
+}
+
+func marshalKey(t *testing.T, key *ecdsa.PrivateKey) []byte {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+	return der
+}
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	t.Helper()
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func readFile(t *testing.T, path string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	return data
+}
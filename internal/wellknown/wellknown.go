@@ -0,0 +1,137 @@
+// Package wellknown lists the conventional files that hosts publish at fixed paths,
+// such as robots.txt, sitemaps and web app manifests, and interprets their content.
+package wellknown
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/model"
+)
+
+// Paths are requested from every host when well-known discovery is enabled.
+var Paths = []string{
+	"/robots.txt",
+	"/sitemap.xml",
+	"/sitemap_index.xml",
+	"/security.txt",
+	"/humans.txt",
+	"/manifest.json",
+	"/site.webmanifest",
+	"/manifest.webmanifest",
+	"/asset-manifest.json",
+	"/crossdomain.xml",
+	"/clientaccesspolicy.xml",
+	"/.well-known/security.txt",
+	"/.well-known/openid-configuration",
+	"/.well-known/oauth-authorization-server",
+	"/.well-known/assetlinks.json",
+	"/.well-known/apple-app-site-association",
+	"/.well-known/host-meta",
+	"/.well-known/host-meta.json",
+	"/.well-known/change-password",
+}
+
+// Origin returns the scheme and host of resource, or false when it is not an HTTP URL.
+func Origin(resource string) (string, bool) {
+	parsed, err := url.Parse(resource)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return "", false
+	}
+	return strings.ToLower(parsed.Scheme + "://" + parsed.Host), true
+}
+
+// URLs returns the well-known file URLs of origin.
+func URLs(origin string) []string {
+	urls := make([]string, len(Paths))
+	for idx, p := range Paths {
+		urls[idx] = origin + p
+	}
+	return urls
+}
+
+// Valid reports whether content looks like the file the URL names. Many hosts answer
+// unknown paths with their home page, which must not be mistaken for the file.
+func Valid(resource, content string) bool {
+	trimmed := strings.TrimSpace(content)
+	if trimmed == "" {
+		return false
+	}
+	lower := strings.ToLower(trimmed)
+	if strings.HasPrefix(lower, "<!doctype html") || strings.HasPrefix(lower, "<html") {
+		return false
+	}
+
+	name := resource
+	if parsed, err := url.Parse(resource); err == nil {
+		name = parsed.Path
+	}
+	name = strings.ToLower(path.Base(name))
+
+	switch {
+	case name == "robots.txt":
+		return strings.Contains(lower, "user-agent:") || strings.Contains(lower, "disallow:") || strings.Contains(lower, "sitemap:")
+	case name == "security.txt":
+		return strings.Contains(lower, "contact:")
+	case strings.HasSuffix(name, ".xml"):
+		return strings.HasPrefix(lower, "<?xml") || strings.HasPrefix(lower, "<")
+	case strings.HasSuffix(name, ".json"), strings.HasSuffix(name, ".webmanifest"),
+		name == "openid-configuration", name == "oauth-authorization-server", name == "apple-app-site-association":
+		return json.Valid([]byte(trimmed))
+	default:
+		return true
+	}
+}
+
+// Robots holds the parts of a robots.txt file that point at content.
+type Robots struct {
+	Disallow []model.Endpoint
+	Sitemaps []string
+}
+
+// ParseRobots extracts the Disallow paths and the sitemap URLs of a robots.txt file.
+// Paths are deduplicated and keep the line they were first seen on.
+func ParseRobots(content string) Robots {
+	var (
+		robots Robots
+		seen   = make(map[string]struct{})
+	)
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if idx := strings.IndexByte(text, '#'); idx != -1 {
+			text = text[:idx]
+		}
+
+		field, value, ok := strings.Cut(text, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		switch strings.ToLower(strings.TrimSpace(field)) {
+		case "disallow":
+			if _, ok := seen[value]; ok {
+				continue
+			}
+			seen[value] = struct{}{}
+			robots.Disallow = append(robots.Disallow, model.Endpoint{
+				Link:    value,
+				Context: strings.TrimSpace(text),
+				Line:    line,
+			})
+		case "sitemap":
+			robots.Sitemaps = append(robots.Sitemaps, value)
+		}
+	}
+
+	return robots
+}
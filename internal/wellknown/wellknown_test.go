@@ -0,0 +1,74 @@
+package wellknown
+
+import "testing"
+
+func TestOrigin(t *testing.T) {
+	origin, ok := Origin("https://App.Example.com:8443/static/main.js?v=1")
+	if !ok || origin != "https://app.example.com:8443" {
+		t.Fatalf("unexpected origin %q (%v)", origin, ok)
+	}
+
+	if _, ok := Origin("file:///tmp/app.js"); ok {
+		t.Fatalf("expected file URLs to have no origin")
+	}
+
+	urls := URLs(origin)
+	if len(urls) != len(Paths) || urls[0] != "https://app.example.com:8443/robots.txt" {
+		t.Fatalf("unexpected well-known URLs: %v", urls)
+	}
+}
+
+func TestValid(t *testing.T) {
+	cases := []struct {
+		url     string
+		content string
+		want    bool
+	}{
+		{"https://example.com/robots.txt", "User-agent: *\nDisallow: /admin", true},
+		{"https://example.com/robots.txt", "<!DOCTYPE html><html><body>Home</body></html>", false},
+		{"https://example.com/sitemap.xml", `<?xml version="1.0"?><urlset></urlset>`, true},
+		{"https://example.com/manifest.json", `{"start_url":"/app"}`, true},
+		{"https://example.com/asset-manifest.json", `<html>not found</html>`, false},
+		{"https://example.com/.well-known/openid-configuration", `{"issuer":"https://example.com"}`, true},
+		{"https://example.com/.well-known/security.txt", "Contact: mailto:security@example.com", true},
+		{"https://example.com/.well-known/security.txt", "Welcome to our site", false},
+		{"https://example.com/humans.txt", "   ", false},
+	}
+
+	for _, tc := range cases {
+		if got := Valid(tc.url, tc.content); got != tc.want {
+			t.Errorf("Valid(%q, %q) = %v, want %v", tc.url, tc.content, got, tc.want)
+		}
+	}
+}
+
+func TestParseRobots(t *testing.T) {
+	content := `# robots for example.com
+User-agent: *
+Disallow: /admin/
+Disallow: /internal/api   # keep crawlers out
+Allow: /public
+Disallow:
+
+User-agent: Googlebot
+Disallow: /admin/
+Sitemap: https://example.com/sitemap-pages.xml
+sitemap: /sitemap-posts.xml
+`
+
+	robots := ParseRobots(content)
+
+	if len(robots.Disallow) != 2 {
+		t.Fatalf("expected 2 disallowed paths, got %+v", robots.Disallow)
+	}
+	if robots.Disallow[0].Link != "/admin/" || robots.Disallow[0].Line != 3 || robots.Disallow[0].Context != "Disallow: /admin/" {
+		t.Fatalf("unexpected first entry: %+v", robots.Disallow[0])
+	}
+	if robots.Disallow[1].Link != "/internal/api" || robots.Disallow[1].Line != 4 {
+		t.Fatalf("unexpected second entry: %+v", robots.Disallow[1])
+	}
+
+	if len(robots.Sitemaps) != 2 || robots.Sitemaps[0] != "https://example.com/sitemap-pages.xml" || robots.Sitemaps[1] != "/sitemap-posts.xml" {
+		t.Fatalf("unexpected sitemaps: %v", robots.Sitemaps)
+	}
+}
@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net/url"
 	"os"
 	"regexp"
@@ -10,13 +12,18 @@ import (
 	"sync"
 	"time"
 
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/browser"
 	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/config"
 	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/gf"
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/imports"
 	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/input"
 	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/model"
 	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/network"
 	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/output"
 	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/parser"
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/proxy"
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/routes"
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/wellknown"
 )
 
 const (
@@ -68,10 +75,39 @@ func main() {
 		progressOut = os.Stderr
 	}
 
-	targets, err := input.ResolveTargets(cfg)
+	stream, err := input.Open(cfg)
 	if err != nil {
 		exitWithError(err)
 	}
+	defer stream.Close()
+
+	if _, err := proxy.FromConfig(cfg); err != nil {
+		exitWithError(fmt.Errorf("invalid proxy configuration: %w", err))
+	}
+
+	if _, err := browser.LoadInteractions(cfg.Interactions); err != nil {
+		exitWithError(fmt.Errorf("invalid interaction script: %w", err))
+	}
+
+	if cfg.Login != "" {
+		fmt.Fprintf(progressOut, "Logging in with recipe: %s\n", cfg.Login)
+		session, headers, err := browser.Login(context.Background(), cfg)
+		if err != nil {
+			exitWithError(fmt.Errorf("login failed: %w", err))
+		}
+		cfg.Session = session
+		cfg.Headers = append(cfg.Headers, headers...)
+	}
+
+	if cfg.Render && cfg.Verbose {
+		for _, mismatch := range browser.HeaderMismatches(cfg) {
+			if mismatch.Browser == "" {
+				fmt.Fprintf(progressOut, "Header %s is sent by the HTTP fetcher but cannot be set by the headless browser\n", mismatch.Name)
+				continue
+			}
+			fmt.Fprintf(progressOut, "Header %s is %q for the HTTP fetcher but %q for the headless browser\n", mismatch.Name, mismatch.HTTP, mismatch.Browser)
+		}
+	}
 
 	var filterRegex *regexp.Regexp
 	if cfg.Regex != "" {
@@ -90,13 +126,48 @@ func main() {
 		htmlBuilder = &strings.Builder{}
 	}
 
-	reports := make([]output.ResourceReport, 0, len(targets))
+	var reports []output.ResourceReport
 	var reportsMu sync.Mutex
 	var outputMu sync.Mutex
 
+	// gf rules are loaded up front so content rules can run while each resource's full
+	// content is still in memory.
+	var gfDefinitions []gf.Definition
+	var gfContentFindings []gf.Finding
+	if cfg.GFAll || len(cfg.GFPatterns) > 0 {
+		gfDefinitions, err = gf.LoadDefinitions(cfg.GFPatterns, cfg.GFAll, cfg.GFPath)
+		if err != nil {
+			exitWithError(fmt.Errorf("unable to load gf rules: %w", err))
+		}
+	}
+	// Built-in secrets rules are reported alongside the gf rules.
+	if cfg.SecretsAll || len(cfg.SecretRules) > 0 {
+		secretDefinitions, err := gf.LoadSecrets(cfg.SecretRules, cfg.SecretsAll)
+		if err != nil {
+			exitWithError(fmt.Errorf("unable to load secrets rules: %w", err))
+		}
+		if cfg.Verbose {
+			fmt.Fprintf(progressOut, "Using built-in secrets ruleset %s (%d rules)\n", gf.SecretsVersion(), len(secretDefinitions))
+		}
+		gfDefinitions = append(gfDefinitions, secretDefinitions...)
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// runtimeScripts deduplicates scripts captured while rendering when recursion
+	// does not provide a per-target visited set.
+	runtimeScripts := newVisitedSet()
+
+	// wellKnownSeen records the origins whose well-known files were queued, and the
+	// sitemaps queued from their robots.txt files.
+	wellKnownSeen := newVisitedSet()
+	// wellKnownVisited deduplicates the resources found through well-known files when
+	// recursion does not provide a per-target visited set.
+	wellKnownVisited := newVisitedSet()
+	// inputOrigins collects the origins of the input targets as they are read.
+	inputOrigins := newVisitedSet()
+
 	tasks := make(chan resourceTask, cfg.Workers)
 	var taskWg sync.WaitGroup
 	var workerWg sync.WaitGroup
@@ -148,7 +219,22 @@ func main() {
 					fmt.Fprintf(progressOut, "Running against: %s\n\n", task.target.URL)
 				}
 
-				content, err := resolveContent(ctx, task.target, cfg)
+				if cfg.WellKnown && !task.target.Prefetched {
+					enqueueWellKnown(ctx, cfg, task, inputOrigins, wellKnownSeen, wellKnownVisited, enqueue, progressOut)
+				}
+
+				fetchCfg := cfg
+				if task.wellKnown {
+					// Well-known files are plain text, XML or JSON; rendering adds nothing.
+					fetchCfg.Render = false
+				}
+
+				result, err := resolveContent(ctx, task.target, fetchCfg)
+				if task.wellKnown && (err != nil || result.Status < 200 || result.Status >= 300 || !wellknown.Valid(task.target.URL, result.Content)) {
+					// Most hosts publish only a few of these files; missing ones are not errors.
+					taskWg.Done()
+					continue
+				}
 				if err != nil {
 					if network.IsTimeoutError(err) {
 						fmt.Fprintf(progressOut, "Request timed out for: %s\n", task.target.URL)
@@ -175,19 +261,50 @@ func main() {
 
 				// Include context when outputting to HTML or JSON
 				includeContext := mode.Includes(output.ModeHTML) || hasJSONOutput
-				endpoints := parser.FindEndpoints(content, endpointRegex, includeContext, filterRegex, true)
-				report := output.ResourceReport{Resource: task.target.URL, Endpoints: endpoints}
+				endpoints := parser.FindEndpoints(result.Content, endpointRegex, includeContext, filterRegex, true)
+				endpoints = appendStateEndpoints(endpoints, result.State, endpointRegex, filterRegex, includeContext)
+				if task.wellKnown && isRobots(task.target.URL) {
+					robots := wellknown.ParseRobots(result.Content)
+					endpoints = appendRobotsEndpoints(endpoints, robots.Disallow, filterRegex, includeContext)
+					enqueueSitemaps(ctx, cfg, task, robots.Sitemaps, wellKnownSeen, enqueue)
+				}
+				report := output.ResourceReport{
+					Resource:   task.target.URL,
+					Method:     task.target.Method,
+					Status:     task.target.Status,
+					MIMEType:   task.target.MIMEType,
+					CapturedAt: task.target.CapturedAt,
+					Endpoints:  endpoints,
+					Redirects:  result.Redirects,
+					Runtime:    result.Requests,
+					Routes:     routes.Find(result.Content),
+					State:      result.State,
+				}
 
 				outputMu.Lock()
 				render(mode, report, htmlBuilder)
 				outputMu.Unlock()
 
+				contentFindings := gf.FindInContent(task.target.URL, result.Content, gfDefinitions)
+
 				reportsMu.Lock()
 				reports = append(reports, report)
+				gfContentFindings = append(gfContentFindings, contentFindings...)
 				reportsMu.Unlock()
 
-				if cfg.Recursive != RecursionDisabled && task.visited != nil {
-					processDiscoveredResources(ctx, cfg, task.target.URL, endpoints, task.visited, enqueue, task.depth, progressOut)
+				if len(result.Scripts) > 0 {
+					seen := task.visited
+					if seen == nil {
+						seen = runtimeScripts
+					}
+					enqueueRuntimeScripts(ctx, cfg, result.Scripts, seen, enqueue, task.depth)
+				}
+
+				// Well-known files are followed one level even without --recursive, so the
+				// sitemaps and scripts they list are analysed.
+				if (cfg.Recursive != RecursionDisabled || task.wellKnown) && task.visited != nil {
+					scripts := discoveredScripts(result.Content, report.Routes)
+					processDiscoveredResources(ctx, cfg, task.target.URL, endpoints, scripts, task.visited, enqueue, task.depth, progressOut)
 				}
 
 				taskWg.Done()
@@ -195,27 +312,66 @@ func main() {
 		}()
 	}
 
-	for _, t := range targets {
-		// Initialize depth based on recursive mode
-		depth := RecursionDisabled
-		if cfg.Recursive == RecursionUnlimited {
-			depth = RecursionUnlimited
-		} else if cfg.Recursive > RecursionDisabled {
-			depth = cfg.Recursive
-		}
+	// Initialize depth based on recursive mode
+	depth := RecursionDisabled
+	if cfg.Recursive == RecursionUnlimited {
+		depth = RecursionUnlimited
+	} else if cfg.Recursive > RecursionDisabled {
+		depth = cfg.Recursive
+	}
 
-		// Detect resource type from the initial target URL
-		rtype := network.DetectResourceType(t.URL)
+	// The input is read while workers run, so huge target lists start immediately. The
+	// reader holds a task slot until the input is exhausted, and blocks while the queue
+	// is full instead of buffering targets.
+	taskWg.Add(1)
+	go func() {
+		defer taskWg.Done()
+		for ctx.Err() == nil {
+			t, err := stream.Next()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				recordError(err)
+				return
+			}
 
-		task := resourceTask{
-			target: t,
-			depth:  depth,
-			rtype:  rtype,
+			if origin, ok := wellknown.Origin(t.URL); ok {
+				inputOrigins.Add(origin)
+			}
+
+			task := resourceTask{
+				target: t,
+				depth:  depth,
+				// Detect resource type from the initial target URL
+				rtype: network.DetectResourceType(t.URL),
+			}
+			if cfg.Recursive != RecursionDisabled {
+				task.visited = newVisitedSet()
+			}
+
+			taskWg.Add(1)
+			select {
+			case tasks <- task:
+			case <-ctx.Done():
+				taskWg.Done()
+			}
 		}
-		if cfg.Recursive != RecursionDisabled {
-			task.visited = newVisitedSet()
+	}()
+
+	stopProgress := func() {}
+	if stream.IsList() {
+		// The periodic lines would interleave with a CLI report, so they go to stderr
+		// whenever the report is written to stdout.
+		statusOut := progressOut
+		if mode.Includes(output.ModeCLI) {
+			statusOut = os.Stderr
 		}
-		enqueue(task)
+		stopProgress = reportProgress(stream, func() int {
+			reportsMu.Lock()
+			defer reportsMu.Unlock()
+			return len(reports)
+		}, statusOut)
 	}
 
 	go func() {
@@ -224,25 +380,25 @@ func main() {
 	}()
 
 	workerWg.Wait()
+	stopProgress()
+	browser.Shutdown()
 
 	if firstErr != nil {
 		exitWithError(firstErr)
 	}
 
 	meta := output.BuildMetadata(reports, generatedAt)
+	if cfg.SecretsAll || len(cfg.SecretRules) > 0 {
+		meta.SecretsVersion = gf.SecretsVersion()
+	}
 
 	// Process GF pattern matching if enabled
 	var gfRules []string
 	var gfFindings []output.GFFinding
 
-	if cfg.GFAll || len(cfg.GFPatterns) > 0 {
-		definitions, err := gf.LoadDefinitions(cfg.GFPatterns, cfg.GFAll, cfg.GFPath)
-		if err != nil {
-			exitWithError(fmt.Errorf("unable to load gf rules: %w", err))
-		}
-
-		rawFindings := gf.FindInReports(reports, definitions)
-		gfRules = gf.RuleNames(definitions)
+	if len(gfDefinitions) > 0 {
+		rawFindings := gf.Merge(gf.FindInReports(reports, gfDefinitions), gfContentFindings)
+		gfRules = gf.RuleNames(gfDefinitions)
 
 		// Convert gf.Finding to output.GFFinding
 		gfFindings = make([]output.GFFinding, len(rawFindings))
@@ -286,21 +442,23 @@ func main() {
 	}
 }
 
-func resolveContent(ctx context.Context, t model.Target, cfg config.Config) (string, error) {
+func resolveContent(ctx context.Context, t model.Target, cfg config.Config) (network.Result, error) {
 	if t.Prefetched {
-		return t.Content, nil
+		return network.Result{Content: t.Content}, nil
 	}
 
 	if strings.HasPrefix(t.URL, "file://") {
-		return input.ResolveFilePath(t.URL)
+		content, err := input.ResolveFilePath(t.URL)
+		return network.Result{Content: content}, err
 	}
 
-	return network.Fetch(ctx, t.URL, cfg)
+	return network.FetchResource(ctx, t.URL, cfg)
 }
 
 // processDiscoveredResources handles recursive processing of discovered endpoints.
 // It validates, filters, and enqueues new resources for processing based on the configured recursion depth.
-func processDiscoveredResources(ctx context.Context, cfg config.Config, baseResource string, endpoints []model.Endpoint, visited *visitedSet,
+// Scripts lists URLs known to load JavaScript, which are followed whatever their extension.
+func processDiscoveredResources(ctx context.Context, cfg config.Config, baseResource string, endpoints []model.Endpoint, scripts []string, visited *visitedSet,
 	enqueue func(resourceTask), depth int, progressOut *os.File) {
 	if visited == nil {
 		return
@@ -315,32 +473,27 @@ func processDiscoveredResources(ctx context.Context, cfg config.Config, baseReso
 	nextDepth := depth
 	if depth > RecursionDisabled {
 		nextDepth = depth - 1
-		if nextDepth == RecursionDisabled {
+		if nextDepth == RecursionDisabled && cfg.Recursive != RecursionDisabled {
 			// This was the last recursion level, print message after processing
 			defer fmt.Fprintf(progressOut, "Maximum recursion depth reached for %s\n", baseResource)
 		}
 	}
 	// depth == RecursionUnlimited stays RecursionUnlimited
 
-	for _, ep := range endpoints {
-		if ctx.Err() != nil {
+	follow := func(resolved string, resourceType network.ResourceType) {
+		// Relative links inside archive entries resolve to URLs without a host.
+		if parsed, err := url.Parse(resolved); err != nil || (parsed.Host == "" && parsed.Scheme != "file") {
 			return
 		}
 
-		// Try to resolve the URL as any supported resource type (JavaScript or Sitemap)
-		resolved, resourceType, ok := network.ResolveURL(ep.Link, baseResource, network.ResourceJavaScript, network.ResourceSitemap)
-		if !ok {
-			continue
-		}
-
 		// Apply scope filtering if configured
 		if cfg.Scope != "" && !network.WithinScope(resolved, cfg.Scope, cfg.ScopeIncludeSubdomains) {
-			continue
+			return
 		}
 
 		// Skip if already visited
 		if !visited.Add(resolved) {
-			continue
+			return
 		}
 
 		// Enqueue the resource for processing
@@ -352,6 +505,217 @@ func processDiscoveredResources(ctx context.Context, cfg config.Config, baseReso
 			rtype:      resourceType,
 		})
 	}
+
+	for _, ep := range endpoints {
+		if ctx.Err() != nil {
+			return
+		}
+
+		// Try to resolve the URL as any supported resource type (JavaScript or Sitemap)
+		resolved, resourceType, ok := network.ResolveURL(ep.Link, baseResource, network.ResourceJavaScript, network.ResourceSitemap)
+		if !ok {
+			continue
+		}
+		follow(resolved, resourceType)
+	}
+
+	for _, script := range scripts {
+		if ctx.Err() != nil {
+			return
+		}
+
+		resolved, ok := network.ResolveScriptURL(script, baseResource)
+		if !ok {
+			continue
+		}
+		follow(resolved, network.ResourceJavaScript)
+	}
+}
+
+// discoveredScripts returns the references in content that always load a script,
+// whatever their extension, such as import("./pages/Admin"): workers, importScripts,
+// dynamic imports, import maps and the chunks of lazily loaded routes.
+func discoveredScripts(content string, found []model.Route) []string {
+	return append(imports.URLs(imports.Find(content)), routes.Modules(found)...)
+}
+
+// appendStateEndpoints adds the endpoints found in captured runtime state that the page
+// content did not already reveal. Their context names the global or storage item.
+func appendStateEndpoints(endpoints []model.Endpoint, state []model.StateEntry, regex, filter *regexp.Regexp, includeContext bool) []model.Endpoint {
+	if len(state) == 0 {
+		return endpoints
+	}
+
+	seen := make(map[string]struct{}, len(endpoints))
+	for _, ep := range endpoints {
+		seen[ep.Link] = struct{}{}
+	}
+
+	for _, entry := range state {
+		for _, ep := range parser.FindEndpoints(entry.Value, regex, false, filter, true) {
+			if _, ok := seen[ep.Link]; ok {
+				continue
+			}
+			seen[ep.Link] = struct{}{}
+
+			ep.Line = 0
+			if includeContext {
+				ep.Context = entry.Source + "." + entry.Name
+			}
+			endpoints = append(endpoints, ep)
+		}
+	}
+	return endpoints
+}
+
+// enqueueRuntimeScripts queues the scripts a rendered page loaded so their bodies are
+// parsed like any other resource. The bodies were captured by the browser, so no
+// additional request is made. Like discovered resources, they sit one level below the
+// page, so a page at the maximum recursion depth queues none.
+func enqueueRuntimeScripts(ctx context.Context, cfg config.Config, scripts []model.Target, visited *visitedSet,
+	enqueue func(resourceTask), depth int) {
+	nextDepth := depth
+	if cfg.Recursive != RecursionDisabled {
+		if depth == RecursionDisabled {
+			return
+		}
+		if depth > RecursionDisabled {
+			nextDepth = depth - 1
+		}
+	}
+
+	for _, script := range scripts {
+		if ctx.Err() != nil {
+			return
+		}
+
+		if cfg.Scope != "" && !network.WithinScope(script.URL, cfg.Scope, cfg.ScopeIncludeSubdomains) {
+			continue
+		}
+
+		if !visited.Add(script.URL) {
+			continue
+		}
+
+		task := resourceTask{
+			target: script,
+			depth:  nextDepth,
+			rtype:  network.ResourceJavaScript,
+		}
+		if cfg.Recursive != RecursionDisabled {
+			task.visited = visited
+		}
+		enqueue(task)
+	}
+}
+
+// enqueueWellKnown queues the well-known files of the task's host the first time an
+// in-scope host is seen. Without --scope, the hosts of the input targets are in scope.
+func enqueueWellKnown(ctx context.Context, cfg config.Config, task resourceTask, inputOrigins *visitedSet,
+	seen, visited *visitedSet, enqueue func(resourceTask), progressOut *os.File) {
+	origin, ok := wellknown.Origin(task.target.URL)
+	if !ok {
+		return
+	}
+
+	if cfg.Scope != "" {
+		if !network.WithinScope(origin, cfg.Scope, cfg.ScopeIncludeSubdomains) {
+			return
+		}
+	} else if !inputOrigins.Has(origin) {
+		return
+	}
+
+	if !seen.Add(origin) {
+		return
+	}
+
+	// What the files list is followed at least one level deep, whatever --recursive says.
+	if task.visited != nil {
+		visited = task.visited
+	}
+	depth := task.depth
+	if depth == RecursionDisabled {
+		depth = 1
+	}
+
+	fmt.Fprintf(progressOut, "Fetching well-known files from: %s\n", origin)
+	for _, resource := range wellknown.URLs(origin) {
+		if ctx.Err() != nil {
+			return
+		}
+		if !visited.Add(resource) {
+			continue
+		}
+		enqueue(resourceTask{
+			target:    model.Target{URL: resource},
+			visited:   visited,
+			depth:     depth,
+			rtype:     network.DetectResourceType(resource),
+			wellKnown: true,
+		})
+	}
+}
+
+// enqueueSitemaps queues the sitemaps referenced by a robots.txt file.
+func enqueueSitemaps(ctx context.Context, cfg config.Config, task resourceTask, sitemaps []string,
+	seen *visitedSet, enqueue func(resourceTask)) {
+	for _, sitemap := range sitemaps {
+		if ctx.Err() != nil {
+			return
+		}
+
+		resolved, ok := network.ResolveScriptURL(sitemap, task.target.URL)
+		if !ok {
+			continue
+		}
+		if cfg.Scope != "" && !network.WithinScope(resolved, cfg.Scope, cfg.ScopeIncludeSubdomains) {
+			continue
+		}
+		if !seen.Add(resolved) {
+			continue
+		}
+		if task.visited != nil && !task.visited.Add(resolved) {
+			continue
+		}
+
+		enqueue(resourceTask{
+			target:    model.Target{URL: resolved},
+			visited:   task.visited,
+			depth:     task.depth,
+			rtype:     network.ResourceSitemap,
+			wellKnown: true,
+		})
+	}
+}
+
+// appendRobotsEndpoints reports the Disallow paths of a robots.txt file as endpoints.
+func appendRobotsEndpoints(endpoints, disallow []model.Endpoint, filter *regexp.Regexp, includeContext bool) []model.Endpoint {
+	seen := make(map[string]struct{}, len(endpoints))
+	for _, ep := range endpoints {
+		seen[ep.Link] = struct{}{}
+	}
+
+	for _, ep := range disallow {
+		if _, ok := seen[ep.Link]; ok {
+			continue
+		}
+		if filter != nil && !filter.MatchString(ep.Link) {
+			continue
+		}
+		seen[ep.Link] = struct{}{}
+
+		if !includeContext {
+			ep.Context = ""
+		}
+		endpoints = append(endpoints, ep)
+	}
+	return endpoints
+}
+
+func isRobots(resource string) bool {
+	parsed, err := url.Parse(resource)
+	return err == nil && parsed.Path == "/robots.txt"
 }
 
 func render(mode output.Mode, report output.ResourceReport, builder *strings.Builder) {
@@ -364,7 +728,34 @@ func render(mode output.Mode, report output.ResourceReport, builder *strings.Bui
 	}
 }
 
+// progressInterval is how often progress is reported while a target list is read.
+const progressInterval = 10 * time.Second
+
+// reportProgress periodically prints how many lines of a target list were read and how
+// many resources were analysed, since the length of a streamed list is unknown. The
+// returned function stops the reports.
+func reportProgress(stream *input.Stream, analysed func() int, progressOut *os.File) func() {
+	ticker := time.NewTicker(progressInterval)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				fmt.Fprintf(progressOut, "Progress: %d lines read, %d resources analysed\n", stream.Lines(), analysed())
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		ticker.Stop()
+		close(done)
+	}
+}
+
 func exitWithError(err error) {
+	browser.Shutdown()
 	fmt.Fprintf(os.Stderr, "Usage: %s [Options] use -h for help\n", os.Args[0])
 	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 	os.Exit(1)
@@ -376,6 +767,9 @@ type resourceTask struct {
 	fromDomain bool
 	depth      int
 	rtype      network.ResourceType
+	// wellKnown marks robots.txt, sitemaps and the other files probed by --well-known,
+	// which are skipped silently when the host does not serve them.
+	wellKnown bool
 }
 
 type visitedSet struct {
@@ -403,6 +797,20 @@ func (v *visitedSet) Add(value string) bool {
 	return true
 }
 
+// Has reports whether value was added to the set.
+func (v *visitedSet) Has(value string) bool {
+	canonical := canonicalURL(value)
+	if canonical == "" {
+		canonical = value
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	_, ok := v.values[canonical]
+	return ok
+}
+
 func canonicalURL(raw string) string {
 	parsed, err := url.Parse(raw)
 	if err != nil {
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/config"
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/model"
+	"github.com/lcalzada-xor/GoLinkfinderEVO/internal/routes"
+)
+
+func TestEnqueueRuntimeScriptsDecrementsDepth(t *testing.T) {
+	scripts := []model.Target{{URL: "https://example.com/chunk.js", Prefetched: true}}
+
+	tests := []struct {
+		name      string
+		recursive int
+		depth     int
+		want      []int
+	}{
+		{"recursion disabled", RecursionDisabled, RecursionDisabled, []int{RecursionDisabled}},
+		{"below the limit", 2, 2, []int{1}},
+		{"at the limit", 2, RecursionDisabled, nil},
+		{"unlimited", RecursionUnlimited, RecursionUnlimited, []int{RecursionUnlimited}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var depths []int
+			enqueue := func(task resourceTask) { depths = append(depths, task.depth) }
+
+			cfg := config.Config{Recursive: tt.recursive}
+			enqueueRuntimeScripts(context.Background(), cfg, scripts, newVisitedSet(), enqueue, tt.depth)
+
+			if len(depths) != len(tt.want) {
+				t.Fatalf("expected %d queued scripts, got %v", len(tt.want), depths)
+			}
+			for i := range depths {
+				if depths[i] != tt.want[i] {
+					t.Fatalf("expected depths %v, got %v", tt.want, depths)
+				}
+			}
+		})
+	}
+}
+
+func TestProcessDiscoveredResourcesFollowsImportsButNotModuleSpecifiers(t *testing.T) {
+	content := `const routes=[{path:"admin",component:()=>import("./pages/Admin")},{path:"legacy",loadChildren:"./legacy/legacy.module#LegacyModule"}];`
+
+	var queued []string
+	enqueue := func(task resourceTask) { queued = append(queued, task.target.URL) }
+
+	scripts := discoveredScripts(content, routes.Find(content))
+	processDiscoveredResources(context.Background(), config.Config{Recursive: RecursionUnlimited}, "https://example.com/static/app.js",
+		nil, scripts, newVisitedSet(), enqueue, RecursionUnlimited, os.Stdout)
+
+	if len(queued) != 1 || queued[0] != "https://example.com/static/pages/Admin" {
+		t.Fatalf("expected only the extensionless import to be queued, got %v", queued)
+	}
+}
+
+func TestEnqueueWellKnownFollowsWithoutRecursion(t *testing.T) {
+	var queued []resourceTask
+	enqueue := func(task resourceTask) { queued = append(queued, task) }
+
+	inputOrigins := newVisitedSet()
+	inputOrigins.Add("https://example.com")
+	input := resourceTask{target: model.Target{URL: "https://example.com/app.js"}}
+
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatalf("open %s: %v", os.DevNull, err)
+	}
+	defer devNull.Close()
+
+	cfg := config.Config{WellKnown: true}
+	enqueueWellKnown(context.Background(), cfg, input, inputOrigins, newVisitedSet(), newVisitedSet(), enqueue, devNull)
+	if len(queued) == 0 {
+		t.Fatalf("expected well-known files to be queued")
+	}
+
+	var robots resourceTask
+	for _, task := range queued {
+		if task.visited == nil || task.depth != 1 || !task.wellKnown {
+			t.Fatalf("expected well-known tasks to be followed one level, got %+v", task)
+		}
+		if isRobots(task.target.URL) {
+			robots = task
+		}
+	}
+
+	queued = nil
+	enqueueSitemaps(context.Background(), cfg, robots, []string{"/sitemap-pages.xml"}, newVisitedSet(), enqueue)
+	if len(queued) != 1 || queued[0].visited == nil || queued[0].depth != 1 {
+		t.Fatalf("expected the robots sitemap to be followed, got %+v", queued)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -24,6 +25,7 @@ func TestRenderModeExtractsDynamicEndpoints(t *testing.T) {
 	if !browser.IsAvailable() {
 		t.Skip("no compatible browser available for render tests")
 	}
+	defer browser.Shutdown()
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
@@ -31,7 +33,7 @@ func TestRenderModeExtractsDynamicEndpoints(t *testing.T) {
 	})
 	mux.HandleFunc("/writer.js", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/javascript")
-		fmt.Fprint(w, `document.write('<a id="docwrite" href="/api/from-document-write">doc</a>');`)
+		fmt.Fprint(w, `document.write('<a id="docwrite" href="/api/from-document-write">doc</a>'); window.__APP_CONFIG__ = {apiBase: '/api/from-config'}; localStorage.setItem('endpoint', '/api/from-storage');`)
 	})
 	mux.HandleFunc("/fetcher.js", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/javascript")
@@ -71,6 +73,40 @@ func TestRenderModeExtractsDynamicEndpoints(t *testing.T) {
 		t.Fatalf("rendered fetch did not contain fetch endpoint; got %v", renderedEndpoints)
 	}
 
+	result, err := network.FetchResource(ctx, baseURL, renderCfg)
+	if err != nil {
+		t.Fatalf("rendered fetch returned error: %v", err)
+	}
+
+	var sawPayload bool
+	for _, req := range result.Requests {
+		if req.URL == server.URL+"/payload" && req.Method == "GET" {
+			sawPayload = true
+		}
+	}
+	if !sawPayload {
+		t.Fatalf("expected runtime fetch of /payload to be captured; got %+v", result.Requests)
+	}
+
+	scripts := make(map[string]string, len(result.Scripts))
+	for _, script := range result.Scripts {
+		scripts[script.URL] = script.Content
+	}
+	if _, ok := scripts[server.URL+"/fetcher.js"]; !ok {
+		t.Fatalf("expected fetcher.js body to be captured; got %v", result.Scripts)
+	}
+
+	state := make(map[string]string, len(result.State))
+	for _, entry := range result.State {
+		state[entry.Source+"."+entry.Name] = entry.Value
+	}
+	if !strings.Contains(state["window.__APP_CONFIG__"], "/api/from-config") {
+		t.Fatalf("expected window.__APP_CONFIG__ to be captured; got %+v", result.State)
+	}
+	if state["localStorage.endpoint"] != "/api/from-storage" {
+		t.Fatalf("expected localStorage to be captured; got %+v", result.State)
+	}
+
 	if _, ok := staticEndpoints["/api/from-document-write"]; ok {
 		t.Fatalf("non rendered fetch unexpectedly contained document.write endpoint")
 	}